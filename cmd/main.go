@@ -2,18 +2,22 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"github.com/bambutcha/taskflow/internal/handler"
+	"github.com/bambutcha/taskflow/internal/model"
 	"github.com/bambutcha/taskflow/internal/repository"
 	"github.com/bambutcha/taskflow/internal/service"
 )
@@ -31,9 +35,29 @@ func main() {
 	log.Info("Taskflow API starting...")
 
 	repo := repository.NewMemoryRepository()
-	taskManager := service.NewTaskManager(repo, config.Workers)
+	taskManager := service.NewTaskManager(repo, service.Options{
+		Workers:          config.Workers,
+		DefaultTimeout:   config.TaskTimeout,
+		RetryPolicy:      config.RetryPolicy,
+		PriorityAging:    config.PriorityAging,
+		Queues:           config.Queues,
+		RateLimits:       config.RateLimits,
+		IdempotencyTTL:   config.IdempotencyTTL,
+		ProgressInterval: config.ProgressInterval,
+		TaskLogSize:      config.TaskLogSize,
+		Artifacts:        repository.NewFileArtifactStore(config.ArtifactDir),
+		MaxResultSize:    config.MaxResultSize,
+		LeaseDuration:    config.LeaseDuration,
+		LeasePolicy:      service.LeasePolicy(config.LeasePolicy),
+		ShutdownPolicy:   service.ShutdownPolicy(config.ShutdownPolicy),
+	})
 	taskHandler := handler.NewTaskHandler(taskManager)
 	healthHandler := handler.NewHealthHandler(taskManager)
+	scheduleHandler := handler.NewScheduleHandler(taskManager)
+	workflowHandler := handler.NewWorkflowHandler(taskManager)
+	batchHandler := handler.NewBatchHandler(taskManager)
+	adminHandler := handler.NewAdminHandler(taskManager)
+	queueHandler := handler.NewQueueHandler(taskManager)
 
 	r := mux.NewRouter()
 
@@ -42,21 +66,51 @@ func main() {
 	r.HandleFunc("/tasks", taskHandler.CreateTask).Methods("POST")
 	r.HandleFunc("/tasks/{id}", taskHandler.GetTask).Methods("GET")
 	r.HandleFunc("/tasks/{id}", taskHandler.DeleteTask).Methods("DELETE")
+	r.HandleFunc("/tasks/{id}/cancel", taskHandler.CancelTask).Methods("POST")
+	r.HandleFunc("/tasks/{id}/logs", taskHandler.GetTaskLogs).Methods("GET")
+	r.HandleFunc("/tasks/{id}/artifacts/{name}", taskHandler.GetArtifact).Methods("GET")
+	r.HandleFunc("/schedules", scheduleHandler.CreateSchedule).Methods("POST")
+	r.HandleFunc("/schedules", scheduleHandler.ListSchedules).Methods("GET")
+	r.HandleFunc("/schedules/{id}", scheduleHandler.GetSchedule).Methods("GET")
+	r.HandleFunc("/schedules/{id}", scheduleHandler.DeleteSchedule).Methods("DELETE")
+	r.HandleFunc("/schedules/{id}/pause", scheduleHandler.PauseSchedule).Methods("POST")
+	r.HandleFunc("/schedules/{id}/resume", scheduleHandler.ResumeSchedule).Methods("POST")
+	r.HandleFunc("/workflows", workflowHandler.CreateWorkflow).Methods("POST")
+	r.HandleFunc("/workflows/{id}", workflowHandler.GetWorkflow).Methods("GET")
+	r.HandleFunc("/batches", batchHandler.CreateBatch).Methods("POST")
+	r.HandleFunc("/batches/{id}", batchHandler.GetBatch).Methods("GET")
+	r.HandleFunc("/admin/workers", adminHandler.GetWorkers).Methods("GET")
+	r.HandleFunc("/admin/workers", adminHandler.SetWorkers).Methods("PUT")
+	r.HandleFunc("/admin/rate-limits", adminHandler.GetRateLimits).Methods("GET")
+	r.HandleFunc("/admin/pause", adminHandler.Pause).Methods("POST")
+	r.HandleFunc("/admin/resume", adminHandler.Resume).Methods("POST")
+	r.HandleFunc("/queues", queueHandler.ListQueues).Methods("GET")
+	r.HandleFunc("/queues/{name}/pause", queueHandler.PauseQueue).Methods("POST")
+	r.HandleFunc("/queues/{name}/resume", queueHandler.ResumeQueue).Methods("POST")
 
 	corsOptions := handlers.AllowedOrigins([]string{"*"})
-	corsMethods := handlers.AllowedMethods([]string{"GET", "POST", "DELETE", "OPTIONS"})
-	corsHeaders := handlers.AllowedHeaders([]string{"Content-Type"})
+	corsMethods := handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	corsHeaders := handlers.AllowedHeaders([]string{"Content-Type", "Idempotency-Key"})
 
 	corsHandler := handlers.CORS(corsOptions, corsMethods, corsHeaders)(r)
 	loggedHandler := handlers.LoggingHandler(os.Stdout, corsHandler)
 
+	// контекст запросов отменяется при остановке, чтобы потоки follow
+	// не держали соединения до конца дедлайна
+	serverCtx, stopRequests := context.WithCancel(context.Background())
+	defer stopRequests()
+
 	srv := &http.Server{
 		Addr:         ":" + config.Port,
 		Handler:      loggedHandler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return serverCtx
+		},
 	}
+	srv.RegisterOnShutdown(stopRequests)
 
 	go func() {
 		log.WithField("port", config.Port).Info("Server starting")
@@ -73,33 +127,64 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// задачи останавливаются, даже если HTTP сервер не успел закрыть соединения
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.WithField("error", err.Error()).Error("Server forced to shutdown")
+	}
+
+	if err := taskManager.Shutdown(ctx); err != nil {
+		log.WithField("error", err.Error()).Warn("Task manager interrupted running tasks")
 	}
 
 	log.Info("Server exited")
 }
 
 type Config struct {
-	Port     string
-	Workers  int
-	LogLevel string
+	Port             string
+	Workers          int
+	LogLevel         string
+	TaskTimeout      time.Duration
+	RetryPolicy      model.RetryPolicy
+	PriorityAging    time.Duration
+	Queues           map[string]int
+	RateLimits       []service.RateLimit
+	IdempotencyTTL   time.Duration
+	ProgressInterval time.Duration
+	TaskLogSize      int
+	ArtifactDir      string
+	MaxResultSize    int
+	LeaseDuration    time.Duration
+	LeasePolicy      string
+	ShutdownPolicy   string
 }
 
 func loadConfig() Config {
-	port := getEnv("PORT", "8080")
-	workersStr := getEnv("WORKERS", "3")
-	logLevel := getEnv("LOG_LEVEL", "info")
-
-	workers, err := strconv.Atoi(workersStr)
-	if err != nil {
-		workers = 3
-	}
+	// явный RETRY_JITTER=0 отключает случайное отклонение
+	retryJitter := getEnvFloat("RETRY_JITTER", 0.1)
 
 	return Config{
-		Port:     port,
-		Workers:  workers,
-		LogLevel: logLevel,
+		Port:        getEnv("PORT", "8080"),
+		Workers:     getEnvInt("WORKERS", 3),
+		LogLevel:    getEnv("LOG_LEVEL", "info"),
+		TaskTimeout: getEnvDuration("TASK_TIMEOUT", 10*time.Minute),
+		RetryPolicy: model.RetryPolicy{
+			MaxAttempts:    getEnvInt("RETRY_MAX_ATTEMPTS", 1),
+			InitialBackoff: model.Duration(getEnvDuration("RETRY_INITIAL_BACKOFF", time.Second)),
+			Multiplier:     getEnvFloat("RETRY_MULTIPLIER", 2),
+			MaxBackoff:     model.Duration(getEnvDuration("RETRY_MAX_BACKOFF", 5*time.Minute)),
+			Jitter:         &retryJitter,
+		},
+		PriorityAging:    getEnvDuration("PRIORITY_AGING", time.Minute),
+		Queues:           getEnvQueues("QUEUES"),
+		RateLimits:       getEnvRateLimits("RATE_LIMITS"),
+		IdempotencyTTL:   getEnvDuration("IDEMPOTENCY_TTL", service.DefaultIdempotencyTTL),
+		ProgressInterval: getEnvDuration("PROGRESS_INTERVAL", service.DefaultProgressInterval),
+		TaskLogSize:      getEnvInt("TASK_LOG_SIZE", service.DefaultTaskLogSize),
+		ArtifactDir:      getEnv("ARTIFACT_DIR", "data/artifacts"),
+		MaxResultSize:    getEnvInt("MAX_RESULT_SIZE", service.DefaultMaxResultSize),
+		LeaseDuration:    getEnvDuration("LEASE_DURATION", service.DefaultLeaseDuration),
+		LeasePolicy:      getEnv("LEASE_POLICY", string(service.LeasePolicyFail)),
+		ShutdownPolicy:   getEnv("SHUTDOWN_POLICY", string(service.ShutdownRequeue)),
 	}
 }
 
@@ -110,6 +195,88 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvQueues разбирает список очередей вида "reports:2,notifications:8";
+// некорректные элементы пропускаются
+func getEnvQueues(key string) map[string]int {
+	queues := make(map[string]int)
+
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		name, workers, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found || name == "" {
+			continue
+		}
+
+		count, err := strconv.Atoi(workers)
+		if err != nil || count < 0 {
+			continue
+		}
+
+		queues[name] = count
+	}
+
+	return queues
+}
+
+// getEnvRateLimits разбирает лимиты вида "type:email=10/20,queue:reports=0.5",
+// где после "=" указана частота в секунду и, через "/", допустимый всплеск
+func getEnvRateLimits(key string) []service.RateLimit {
+	var limits []service.RateLimit
+
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		target, value, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found {
+			continue
+		}
+
+		scope, name, found := strings.Cut(target, ":")
+		if !found {
+			continue
+		}
+
+		rate, burst, _ := strings.Cut(value, "/")
+		limit := service.RateLimit{Scope: scope, Name: name}
+
+		var err error
+		if limit.Rate, err = strconv.ParseFloat(rate, 64); err != nil {
+			continue
+		}
+
+		if burst != "" {
+			if limit.Burst, err = strconv.Atoi(burst); err != nil {
+				continue
+			}
+		}
+
+		limits = append(limits, limit)
+	}
+
+	return limits
+}
+
 func homeHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Taskflow API is running!"))
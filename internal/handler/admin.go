@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+type AdminHandler struct {
+	taskManager *service.TaskManager
+	logger      *logrus.Logger
+}
+
+func NewAdminHandler(taskManager *service.TaskManager) *AdminHandler {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return &AdminHandler{
+		taskManager: taskManager,
+		logger:      logger,
+	}
+}
+
+type SetWorkersRequest struct {
+	Queue   string `json:"queue,omitempty"`
+	Workers *int   `json:"workers"`
+}
+
+type WorkersResponse struct {
+	TargetWorkers int `json:"target_workers"`
+	LiveWorkers   int `json:"live_workers"`
+}
+
+type ProcessingResponse struct {
+	Paused       bool     `json:"paused"`
+	PausedQueues []string `json:"paused_queues"`
+}
+
+func (h *AdminHandler) GetWorkers(w http.ResponseWriter, r *http.Request) {
+	h.writeWorkers(w)
+}
+
+func (h *AdminHandler) SetWorkers(w http.ResponseWriter, r *http.Request) {
+	var req SetWorkersRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.WithField("error", err.Error()).Warn("Invalid JSON in set workers request")
+		writeError(w, h.logger, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.Workers == nil {
+		writeError(w, h.logger, "Workers count is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.Queue == "" {
+		req.Queue = service.DefaultQueue
+	}
+
+	err = h.taskManager.SetQueueWorkers(req.Queue, *req.Workers)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, h.logger, err.Error(), http.StatusNotFound)
+		} else if errors.Is(err, service.ErrValidation) {
+			writeError(w, h.logger, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, service.ErrShuttingDown) {
+			writeError(w, h.logger, err.Error(), http.StatusServiceUnavailable)
+		} else {
+			writeError(w, h.logger, "Failed to resize worker pool", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeWorkers(w)
+}
+
+func (h *AdminHandler) GetRateLimits(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.taskManager.GetRateLimits())
+}
+
+func (h *AdminHandler) writeWorkers(w http.ResponseWriter) {
+	target, live := h.taskManager.GetWorkerCount()
+
+	writeJSON(w, http.StatusOK, WorkersResponse{
+		TargetWorkers: target,
+		LiveWorkers:   live,
+	})
+}
+
+// Pause приостанавливает обработку во всех очередях; отдельные очереди
+// приостанавливаются через /queues/{name}/pause
+func (h *AdminHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.taskManager.Pause()
+	h.writeProcessing(w)
+}
+
+func (h *AdminHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.taskManager.Resume()
+	h.writeProcessing(w)
+}
+
+func (h *AdminHandler) writeProcessing(w http.ResponseWriter) {
+	response := ProcessingResponse{
+		Paused:       h.taskManager.IsPaused(),
+		PausedQueues: []string{},
+	}
+	for _, queue := range h.taskManager.GetQueues() {
+		if queue.Paused {
+			response.PausedQueues = append(response.PausedQueues, queue.Name)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
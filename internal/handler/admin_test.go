@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+func setupTestAdminHandler(workers int) *AdminHandler {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, workers)
+	handler := NewAdminHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+	return handler
+}
+
+func TestAdminHandler_SetWorkers(t *testing.T) {
+	handler := setupTestAdminHandler(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/workers", bytes.NewBufferString(`{"workers": 4}`))
+	w := httptest.NewRecorder()
+
+	handler.SetWorkers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response WorkersResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.TargetWorkers != 4 || response.LiveWorkers != 4 {
+		t.Errorf("Expected 4 target and 4 live workers, got %d and %d", response.TargetWorkers, response.LiveWorkers)
+	}
+}
+
+func TestAdminHandler_SetWorkers_Invalid(t *testing.T) {
+	handler := setupTestAdminHandler(1)
+
+	for _, body := range []string{`{"workers": -1}`, `{}`, `not json`} {
+		req := httptest.NewRequest(http.MethodPut, "/admin/workers", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		handler.SetWorkers(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Body %s: expected status %d, got %d", body, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestAdminHandler_GetRateLimits(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTestingWithOptions(repo, service.Options{
+		RateLimits: []service.RateLimit{{Scope: service.RateLimitScopeType, Name: "email", Rate: 10, Burst: 20}},
+	})
+	handler := NewAdminHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/rate-limits", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetRateLimits(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var limits []service.RateLimitState
+	err := json.NewDecoder(w.Body).Decode(&limits)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(limits) != 1 || limits[0].Name != "email" || limits[0].Tokens != 20 {
+		t.Errorf("Expected full email bucket, got %+v", limits)
+	}
+}
+
+func TestAdminHandler_PauseAndResume(t *testing.T) {
+	handler := setupTestAdminHandler(1)
+
+	send := func(path string, action http.HandlerFunc) ProcessingResponse {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		action(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, path, w.Code, w.Body.String())
+		}
+
+		var response ProcessingResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	if response := send("/admin/pause", handler.Pause); !response.Paused {
+		t.Error("Expected paused after /admin/pause")
+	}
+
+	handler.taskManager.PauseQueue(service.DefaultQueue)
+
+	response := send("/admin/resume", handler.Resume)
+	if response.Paused {
+		t.Error("Expected not paused after /admin/resume")
+	}
+
+	if len(response.PausedQueues) != 1 || response.PausedQueues[0] != service.DefaultQueue {
+		t.Errorf("Expected global resume to keep queue pause, got paused_queues %v", response.PausedQueues)
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// GetArtifact отдает содержимое артефакта задачи как файл для скачивания
+func (h *TaskHandler) GetArtifact(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["id"]
+	name := vars["name"]
+
+	artifact, content, err := h.taskManager.OpenArtifact(taskID, name)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.writeError(w, "Task not found", http.StatusNotFound)
+		} else if errors.Is(err, service.ErrNotFound) {
+			h.writeError(w, "Artifact not found", http.StatusNotFound)
+		} else {
+			h.writeError(w, "Failed to get artifact", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", artifact.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.Name}))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"task_id":  taskID,
+			"artifact": name,
+			"error":    err.Error(),
+		}).Warn("Failed to send artifact")
+	}
+}
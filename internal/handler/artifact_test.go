@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func TestTaskHandler_GetArtifact(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, 1)
+	manager.RegisterExecutor("report", func(ctx context.Context, task *model.Task) (any, error) {
+		return nil, service.ArtifactsFromContext(ctx).Attach("report.csv", "text/csv", strings.NewReader("a,b\n"))
+	})
+	handler := NewTaskHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+
+	manager.CreateTask(service.TaskSpec{ID: "report-1", Type: "report"})
+	for i := 0; i < 50; i++ {
+		task, _ := manager.GetTask("report-1")
+		if task.IsCompleted() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	get := func(id, name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tasks/"+id+"/artifacts/"+name, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id, "name": name})
+		w := httptest.NewRecorder()
+		handler.GetArtifact(w, req)
+		return w
+	}
+
+	w := get("report-1", "report.csv")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	if w.Body.String() != "a,b\n" {
+		t.Errorf("Expected artifact content, got %q", w.Body.String())
+	}
+
+	if w.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("Expected Content-Type text/csv, got %s", w.Header().Get("Content-Type"))
+	}
+
+	if w.Header().Get("Content-Disposition") != "attachment; filename=report.csv" {
+		t.Errorf("Unexpected Content-Disposition %s", w.Header().Get("Content-Disposition"))
+	}
+
+	if w := get("report-1", "missing.csv"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for unknown artifact, got %d", http.StatusNotFound, w.Code)
+	}
+
+	if w := get("missing", "report.csv"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for unknown task, got %d", http.StatusNotFound, w.Code)
+	}
+}
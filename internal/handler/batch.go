@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type BatchHandler struct {
+	taskManager *service.TaskManager
+	logger      *logrus.Logger
+}
+
+func NewBatchHandler(taskManager *service.TaskManager) *BatchHandler {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return &BatchHandler{
+		taskManager: taskManager,
+		logger:      logger,
+	}
+}
+
+type CreateBatchRequest struct {
+	ID         string              `json:"id"`
+	Tasks      []CreateTaskRequest `json:"tasks"`
+	OnComplete *model.TaskTemplate `json:"on_complete,omitempty"`
+}
+
+func (h *BatchHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req CreateBatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.WithField("error", err.Error()).Warn("Invalid JSON in create batch request")
+		writeError(w, h.logger, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.ID == "" {
+		h.logger.Warn("Empty batch ID in create request")
+		writeError(w, h.logger, "Batch ID is required", http.StatusBadRequest)
+		return
+	}
+
+	specs := make([]service.TaskSpec, len(req.Tasks))
+	for i, taskReq := range req.Tasks {
+		specs[i], err = taskReq.taskSpec()
+		if err != nil {
+			writeError(w, h.logger, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	batch, err := h.taskManager.CreateBatch(service.BatchSpec{
+		ID:         req.ID,
+		Tasks:      specs,
+		OnComplete: req.OnComplete,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			writeError(w, h.logger, err.Error(), http.StatusConflict)
+		} else if errors.Is(err, service.ErrValidation) {
+			writeError(w, h.logger, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, service.ErrShuttingDown) {
+			writeError(w, h.logger, "Service is shutting down", http.StatusServiceUnavailable)
+		} else {
+			writeError(w, h.logger, "Failed to create batch", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, batch)
+}
+
+func (h *BatchHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	batchID := mux.Vars(r)["id"]
+
+	batch, err := h.taskManager.GetBatch(batchID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, h.logger, "Batch not found", http.StatusNotFound)
+		} else {
+			writeError(w, h.logger, "Failed to get batch", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, batch)
+}
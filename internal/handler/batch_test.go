@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func setupTestBatchHandler() *BatchHandler {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, 0)
+	handler := NewBatchHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+	return handler
+}
+
+func TestBatchHandler_CreateAndGet(t *testing.T) {
+	handler := setupTestBatchHandler()
+
+	body := `{"id": "import", "tasks": [{"payload": {"row": 1}}, {"payload": {"row": 2}, "delay": "1h"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/batches", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateBatch(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/batches/import", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "import"})
+	w = httptest.NewRecorder()
+
+	handler.GetBatch(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var batch model.Batch
+	err := json.NewDecoder(w.Body).Decode(&batch)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if batch.State != model.BatchRunning {
+		t.Errorf("Expected state %s, got %s", model.BatchRunning, batch.State)
+	}
+
+	if batch.Counts[model.StatusPending] != 1 || batch.Counts[model.StatusScheduled] != 1 {
+		t.Errorf("Expected one pending and one scheduled task, got %v", batch.Counts)
+	}
+}
+
+func TestBatchHandler_CreateBatch_Invalid(t *testing.T) {
+	handler := setupTestBatchHandler()
+
+	body := `{"id": "broken", "tasks": [{"type": "missing"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/batches", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateBatch(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBatchHandler_GetBatch_NotFound(t *testing.T) {
+	handler := setupTestBatchHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/batches/missing", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "missing"})
+	w := httptest.NewRecorder()
+
+	handler.GetBatch(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
+	"github.com/bambutcha/taskflow/internal/model"
 	"github.com/bambutcha/taskflow/internal/service"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -26,7 +31,60 @@ func NewTaskHandler(taskManager *service.TaskManager) *TaskHandler {
 }
 
 type CreateTaskRequest struct {
-	ID string `json:"id"`
+	ID               string             `json:"id,omitempty"`
+	IDPrefix         string             `json:"id_prefix,omitempty"`
+	Type             string             `json:"type"`
+	Payload          json.RawMessage    `json:"payload,omitempty"`
+	Queue            string             `json:"queue,omitempty"`
+	Priority         int                `json:"priority"`
+	ConcurrencyKey   string             `json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int                `json:"concurrency_limit,omitempty"`
+	RunAt            *time.Time         `json:"run_at,omitempty"`
+	Delay            model.Duration     `json:"delay,omitempty"`
+	Timeout          model.Duration     `json:"timeout,omitempty"`
+	RetryPolicy      *model.RetryPolicy `json:"retry_policy,omitempty"`
+	DependsOn        []string           `json:"depends_on,omitempty"`
+}
+
+// taskSpec проверяет время запуска и переводит запрос в спецификацию задачи
+func (req CreateTaskRequest) taskSpec() (service.TaskSpec, error) {
+	if req.RunAt != nil && req.Delay != 0 {
+		return service.TaskSpec{}, fmt.Errorf("only one of run_at and delay can be set")
+	}
+
+	if req.Delay < 0 {
+		return service.TaskSpec{}, fmt.Errorf("delay must not be negative")
+	}
+
+	var runAt time.Time
+	if req.RunAt != nil {
+		runAt = *req.RunAt
+	} else if req.Delay > 0 {
+		runAt = time.Now().Add(req.Delay.Duration())
+	}
+
+	return service.TaskSpec{
+		ID:               req.ID,
+		IDPrefix:         req.IDPrefix,
+		Type:             req.Type,
+		Payload:          req.Payload,
+		Queue:            req.Queue,
+		Priority:         req.Priority,
+		ConcurrencyKey:   req.ConcurrencyKey,
+		ConcurrencyLimit: req.ConcurrencyLimit,
+		RunAt:            runAt,
+		Timeout:          req.Timeout.Duration(),
+		RetryPolicy:      req.RetryPolicy,
+		DependsOn:        req.DependsOn,
+	}, nil
+}
+
+// fingerprint отличает повтор того же запроса от другого запроса с тем же
+// Idempotency-Key; считается по разобранному телу, поэтому не зависит от форматирования
+func (req CreateTaskRequest) fingerprint() string {
+	body, _ := json.Marshal(req)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
 }
 
 type ErrorResponse struct {
@@ -42,22 +100,39 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.ID == "" {
-		h.logger.Warn("Empty task ID in create request")
-		h.writeError(w, "Task ID is required", http.StatusBadRequest)
+	spec, err := req.taskSpec()
+	if err != nil {
+		h.logger.WithField("error", err.Error()).Warn("Invalid run time in create request")
+		h.writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	task, err := h.taskManager.CreateTask(req.ID)
+	var task *model.Task
+	var replayed bool
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		task, replayed, err = h.taskManager.CreateTaskIdempotent(key, req.fingerprint(), spec)
+	} else {
+		task, err = h.taskManager.CreateTask(spec)
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
+		if errors.Is(err, service.ErrIdempotencyMismatch) {
+			h.writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		} else if errors.Is(err, service.ErrConflict) {
 			h.writeError(w, err.Error(), http.StatusConflict)
+		} else if errors.Is(err, service.ErrValidation) {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, service.ErrShuttingDown) {
+			h.writeError(w, "Service is shutting down", http.StatusServiceUnavailable)
 		} else {
 			h.writeError(w, "Failed to create task", http.StatusInternalServerError)
 		}
 		return
 	}
 
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(task)
@@ -75,7 +150,7 @@ func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
 
 	task, err := h.taskManager.GetTask(taskID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, service.ErrNotFound) {
 			h.writeError(w, "Task not found", http.StatusNotFound)
 		} else {
 			h.writeError(w, "Failed to get task", http.StatusInternalServerError)
@@ -100,9 +175,9 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 
 	err := h.taskManager.DeleteTask(taskID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, service.ErrNotFound) {
 			h.writeError(w, "Task not found", http.StatusNotFound)
-		} else if strings.Contains(err.Error(), "cannot delete running task") {
+		} else if errors.Is(err, service.ErrConflict) {
 			h.writeError(w, "Cannot delete running task", http.StatusConflict)
 		} else {
 			h.writeError(w, "Failed to delete task", http.StatusInternalServerError)
@@ -113,15 +188,33 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *TaskHandler) writeError(w http.ResponseWriter, message string, statusCode int) {
-	h.logger.WithFields(logrus.Fields{
-		"status_code": statusCode,
-		"message":     message,
-	}).Warn("Sending error response")
+func (h *TaskHandler) CancelTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["id"]
+
+	if taskID == "" {
+		h.logger.Warn("Empty task ID in cancel request")
+		h.writeError(w, "Task ID is required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.taskManager.CancelTask(taskID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			h.writeError(w, "Task not found", http.StatusNotFound)
+		} else if errors.Is(err, service.ErrConflict) {
+			h.writeError(w, "Cannot cancel finished task", http.StatusConflict)
+		} else {
+			h.writeError(w, "Failed to cancel task", http.StatusInternalServerError)
+		}
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
 
-	errorResp := ErrorResponse{Error: message}
-	json.NewEncoder(w).Encode(errorResp)
+func (h *TaskHandler) writeError(w http.ResponseWriter, message string, statusCode int) {
+	writeError(w, h.logger, message, statusCode)
 }
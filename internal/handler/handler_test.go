@@ -2,10 +2,12 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bambutcha/taskflow/internal/model"
 	"github.com/bambutcha/taskflow/internal/repository"
@@ -60,7 +62,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 func TestTaskHandler_GetTask(t *testing.T) {
 	handler := setupTestHandler()
 
-	_, err := handler.taskManager.CreateTask("get-test-task")
+	_, err := handler.taskManager.CreateTask(service.TaskSpec{ID: "get-test-task"})
 	if err != nil {
 		t.Fatalf("Failed to create task: %v", err)
 	}
@@ -86,10 +88,40 @@ func TestTaskHandler_GetTask(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_GetTask_JSONResult(t *testing.T) {
+	handler := setupTestHandler()
+	handler.taskManager.RegisterExecutor("stats", func(ctx context.Context, task *model.Task) (any, error) {
+		return json.RawMessage(`{"rows":3,"tables":["users"]}`), nil
+	})
+
+	_, err := handler.taskManager.CreateTask(service.TaskSpec{ID: "stats-task", Type: "stats"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	var body map[string]json.RawMessage
+	for i := 0; i < 50; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/tasks/stats-task", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "stats-task"})
+		w := httptest.NewRecorder()
+
+		handler.GetTask(w, req)
+		json.NewDecoder(w.Body).Decode(&body)
+		if string(body["status"]) == `"completed"` {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if string(body["result"]) != `{"rows":3,"tables":["users"]}` {
+		t.Errorf("Expected result returned verbatim, got %s", body["result"])
+	}
+}
+
 func TestTaskHandler_DeleteTask(t *testing.T) {
 	handler := setupTestHandlerNoWorkers()
 
-	_, err := handler.taskManager.CreateTask("delete-test-task")
+	_, err := handler.taskManager.CreateTask(service.TaskSpec{ID: "delete-test-task"})
 	if err != nil {
 		t.Fatalf("Failed to create task: %v", err)
 	}
@@ -109,3 +141,355 @@ func TestTaskHandler_DeleteTask(t *testing.T) {
 		t.Error("Expected task to be deleted, but it still exists")
 	}
 }
+
+func TestTaskHandler_CreateTask_UnknownType(t *testing.T) {
+	handler := setupTestHandler()
+
+	reqBody := CreateTaskRequest{ID: "test-task-1", Type: "missing"}
+	jsonData, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_WithPayload(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	body := `{"id": "payload-task", "payload": {"customer_id": 42, "path": "/tmp/report.csv"}}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks/payload-task", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "payload-task"})
+	w = httptest.NewRecorder()
+
+	handler.GetTask(w, req)
+
+	var task model.Task
+	err := json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	var payload map[string]interface{}
+	err = json.Unmarshal(task.Payload, &payload)
+	if err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	if payload["path"] != "/tmp/report.csv" {
+		t.Errorf("Expected payload path '/tmp/report.csv', got '%v'", payload["path"])
+	}
+}
+
+func TestTaskHandler_CancelTask(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	_, err := handler.taskManager.CreateTask(service.TaskSpec{ID: "cancel-test-task"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/cancel-test-task/cancel", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "cancel-test-task"})
+	w := httptest.NewRecorder()
+
+	handler.CancelTask(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var task model.Task
+	err = json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Errorf("Failed to decode response: %v", err)
+	}
+
+	if task.Status != model.StatusCancelled {
+		t.Errorf("Expected status '%s', got '%s'", model.StatusCancelled, task.Status)
+	}
+
+	w = httptest.NewRecorder()
+	handler.CancelTask(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d on repeated cancel, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_WithTimeout(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	body := `{"id": "timeout-task", "timeout": "90s"}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var task model.Task
+	err := json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if task.Timeout.Duration() != 90*time.Second {
+		t.Errorf("Expected timeout 90s, got %s", task.Timeout.Duration())
+	}
+
+	body = `{"id": "bad-timeout-task", "timeout": "-5s"}`
+	req = httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for negative timeout, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_WithPriority(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	body := `{"id": "priority-task", "priority": 7}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var task model.Task
+	err := json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if task.Priority != 7 {
+		t.Errorf("Expected priority 7, got %d", task.Priority)
+	}
+
+	body = `{"id": "bad-priority-task", "priority": 12}`
+	req = httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid priority, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_Delayed(t *testing.T) {
+	handler := setupTestHandler()
+
+	body := `{"id": "delayed-task", "delay": "1h"}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var task model.Task
+	err := json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if task.Status != model.StatusScheduled {
+		t.Errorf("Expected status '%s', got '%s'", model.StatusScheduled, task.Status)
+	}
+
+	if task.RunAt == nil || task.RunAt.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("Expected run_at about an hour ahead, got %v", task.RunAt)
+	}
+
+	body = `{"id": "conflicting-task", "delay": "1h", "run_at": "2030-01-01T02:00:00Z"}`
+	req = httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for run_at with delay, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_WithDependencies(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "parent-task"})
+
+	body := `{"id": "child-task", "depends_on": ["parent-task"]}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var task model.Task
+	json.NewDecoder(w.Body).Decode(&task)
+
+	if task.Status != model.StatusBlocked {
+		t.Errorf("Expected status '%s', got '%s'", model.StatusBlocked, task.Status)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks/parent-task", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "parent-task"})
+	w = httptest.NewRecorder()
+
+	handler.GetTask(w, req)
+
+	var parent model.Task
+	json.NewDecoder(w.Body).Decode(&parent)
+
+	if len(parent.Dependents) != 1 || parent.Dependents[0] != "child-task" {
+		t.Errorf("Expected dependents [child-task], got %v", parent.Dependents)
+	}
+
+	body = `{"id": "orphan-task", "depends_on": ["missing-task"]}`
+	req = httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for unknown dependency, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_WithConcurrencyKey(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	body := `{"id": "account-sync", "concurrency_key": "customer-42"}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var task model.Task
+	err := json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if task.ConcurrencyKey != "customer-42" || task.ConcurrencyLimit != 1 {
+		t.Errorf("Expected key 'customer-42' with limit 1, got '%s' with %d", task.ConcurrencyKey, task.ConcurrencyLimit)
+	}
+
+	body = `{"id": "bad-limit-task", "concurrency_limit": 2}`
+	req = httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for limit without key, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_IdempotencyKey(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "client-retry-1")
+		w := httptest.NewRecorder()
+		handler.CreateTask(w, req)
+		return w
+	}
+
+	first := send(`{"id": "idempotent-task", "payload": {"n": 1}}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, first.Code)
+	}
+
+	// тот же запрос в другом форматировании считается повтором
+	retry := send(`{"payload": {"n":1}, "id": "idempotent-task"}`)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d for retry, got %d: %s", http.StatusCreated, retry.Code, retry.Body.String())
+	}
+
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected original response on retry, got %s", retry.Body.String())
+	}
+
+	if retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected Idempotent-Replayed header on retry")
+	}
+
+	conflict := send(`{"id": "idempotent-task", "payload": {"n": 2}}`)
+	if conflict.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for different body, got %d", http.StatusUnprocessableEntity, conflict.Code)
+	}
+}
+
+func TestTaskHandler_CreateTask_GeneratedID(t *testing.T) {
+	handler := setupTestHandlerNoWorkers()
+
+	body := `{"id_prefix": "report"}`
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var task model.Task
+	err := json.NewDecoder(w.Body).Decode(&task)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(task.ID) != len("report_")+26 || task.ID[:len("report_")] != "report_" {
+		t.Errorf("Expected generated ID with prefix 'report_', got '%s'", task.ID)
+	}
+
+	body = `{"id_prefix": "bad prefix"}`
+	req = httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	handler.CreateTask(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid prefix, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/bambutcha/taskflow/internal/model"
 	"github.com/bambutcha/taskflow/internal/service"
 	"github.com/sirupsen/logrus"
 )
@@ -37,12 +38,26 @@ type HealthResponse struct {
 }
 
 type HealthMetrics struct {
-	ActiveWorkers  int `json:"active_workers"`
-	TotalTasks     int `json:"total_tasks"`
-	PendingTasks   int `json:"pending_tasks"`
-	RunningTasks   int `json:"running_tasks"`
-	CompletedTasks int `json:"completed_tasks"`
-	FailedTasks    int `json:"failed_tasks"`
+	ActiveWorkers     int                     `json:"active_workers"`
+	TargetWorkers     int                     `json:"target_workers"`
+	TotalTasks        int                     `json:"total_tasks"`
+	ScheduledTasks    int                     `json:"scheduled_tasks"`
+	BlockedTasks      int                     `json:"blocked_tasks"`
+	PendingTasks      int                     `json:"pending_tasks"`
+	PendingByPriority map[int]int             `json:"pending_by_priority"`
+	RunningTasks      int                     `json:"running_tasks"`
+	CompletedTasks    int                     `json:"completed_tasks"`
+	FailedTasks       int                     `json:"failed_tasks"`
+	CancelledTasks    int                     `json:"cancelled_tasks"`
+	TimedOutTasks     int                     `json:"timed_out_tasks"`
+	Queues            map[string]QueueMetrics `json:"queues"`
+}
+
+type QueueMetrics struct {
+	Workers      int  `json:"workers"`
+	PendingTasks int  `json:"pending_tasks"`
+	RunningTasks int  `json:"running_tasks"`
+	Paused       bool `json:"paused"`
 }
 
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
@@ -53,11 +68,21 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	checks := h.performChecks()
 
 	status := "healthy"
-	for _, checkStatus := range checks {
-		if checkStatus != "ok" {
-			status = "unhealthy"
-			break
+	for name, checkStatus := range checks {
+		if checkStatus == "ok" {
+			continue
+		}
+
+		// на паузе API продолжает принимать задачи, поэтому сервис не считается неработоспособным
+		if name == "processing" {
+			if status == "healthy" {
+				status = "degraded"
+			}
+			continue
 		}
+
+		status = "unhealthy"
+		break
 	}
 
 	response := HealthResponse{
@@ -88,36 +113,74 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HealthHandler) collectMetrics() HealthMetrics {
+	targetWorkers, liveWorkers := h.taskManager.GetWorkerCount()
+
 	tasks, err := h.taskManager.GetAllTasks()
 	if err != nil {
 		h.logger.WithField("error", err.Error()).Warn("Failed to collect task metrics")
 		return HealthMetrics{
-			ActiveWorkers: h.taskManager.GetWorkerCount(),
+			ActiveWorkers: liveWorkers,
+			TargetWorkers: targetWorkers,
 		}
 	}
 
-	var pending, running, completed, failed int
+	var scheduled, blocked, pending, running, completed, failed, cancelled, timedOut int
+	pendingByPriority := make(map[int]int)
+
+	queues := make(map[string]QueueMetrics)
+	for _, queue := range h.taskManager.GetQueues() {
+		queues[queue.Name] = QueueMetrics{
+			Workers: queue.Workers,
+			Paused:  queue.Paused,
+		}
+	}
 
 	for _, task := range tasks {
 		switch task.Status {
+		case "scheduled":
+			scheduled++
+		case "blocked":
+			blocked++
 		case "pending":
 			pending++
+			pendingByPriority[task.Priority]++
+			if queue, exists := queues[task.Queue]; exists {
+				queue.PendingTasks++
+				queues[task.Queue] = queue
+			}
 		case "running":
 			running++
+			if queue, exists := queues[task.Queue]; exists {
+				queue.RunningTasks++
+				queues[task.Queue] = queue
+			}
 		case "completed":
 			completed++
 		case "failed":
 			failed++
+		case "cancelled":
+			cancelled++
+		}
+
+		if task.ErrorCode == model.ErrorCodeDeadlineExceeded {
+			timedOut++
 		}
 	}
 
 	return HealthMetrics{
-		ActiveWorkers:  h.taskManager.GetWorkerCount(),
-		TotalTasks:     len(tasks),
-		PendingTasks:   pending,
-		RunningTasks:   running,
-		CompletedTasks: completed,
-		FailedTasks:    failed,
+		ActiveWorkers:     liveWorkers,
+		TargetWorkers:     targetWorkers,
+		TotalTasks:        len(tasks),
+		ScheduledTasks:    scheduled,
+		BlockedTasks:      blocked,
+		PendingTasks:      pending,
+		PendingByPriority: pendingByPriority,
+		RunningTasks:      running,
+		CompletedTasks:    completed,
+		FailedTasks:       failed,
+		CancelledTasks:    cancelled,
+		TimedOutTasks:     timedOut,
+		Queues:            queues,
 	}
 }
 
@@ -125,10 +188,15 @@ func (h *HealthHandler) performChecks() map[string]string {
 	checks := make(map[string]string)
 
 	checks["workers"] = "ok"
-	if h.taskManager.GetWorkerCount() == 0 {
+	if targetWorkers, _ := h.taskManager.GetWorkerCount(); targetWorkers == 0 {
 		checks["workers"] = "no_workers"
 	}
 
+	checks["processing"] = "ok"
+	if h.taskManager.IsPaused() {
+		checks["processing"] = "paused"
+	}
+
 	checks["memory"] = "ok"
 
 	checks["storage"] = "ok"
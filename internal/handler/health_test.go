@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bambutcha/taskflow/internal/repository"
 	"github.com/bambutcha/taskflow/internal/service"
@@ -61,8 +62,8 @@ func TestHealthHandler_Health(t *testing.T) {
 func TestHealthHandler_Health_WithTasks(t *testing.T) {
 	handler := setupTestHealthHandler()
 
-	handler.taskManager.CreateTask("test-task-1")
-	handler.taskManager.CreateTask("test-task-2")
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "test-task-1"})
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "test-task-2"})
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
@@ -113,3 +114,142 @@ func TestHealthHandler_Health_NoWorkers(t *testing.T) {
 		t.Errorf("Expected workers check 'no_workers', got '%s'", response.Checks["workers"])
 	}
 }
+
+func TestHealthHandler_Health_Paused(t *testing.T) {
+	handler := setupTestHealthHandler()
+	handler.taskManager.Pause()
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	handler.Health(w, req)
+
+	// пауза не должна выводить сервис из балансировки
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response HealthResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	if err != nil {
+		t.Errorf("Failed to decode response: %v", err)
+	}
+
+	if response.Status != "degraded" {
+		t.Errorf("Expected status 'degraded', got '%s'", response.Status)
+	}
+
+	if response.Checks["processing"] != "paused" {
+		t.Errorf("Expected processing check 'paused', got '%s'", response.Checks["processing"])
+	}
+
+	handler.taskManager.Resume()
+	w = httptest.NewRecorder()
+	handler.Health(w, req)
+
+	response = HealthResponse{}
+	json.NewDecoder(w.Body).Decode(&response)
+	if w.Code != http.StatusOK || response.Status != "healthy" {
+		t.Errorf("Expected healthy %d after resume, got %s %d", http.StatusOK, response.Status, w.Code)
+	}
+}
+
+func TestHealthHandler_Health_TimedOutTasks(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTestingWithOptions(repo, service.Options{
+		Workers:        1,
+		DefaultTimeout: 10 * time.Millisecond,
+	})
+	handler := NewHealthHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "timeout-task"})
+	time.Sleep(100 * time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	handler.Health(w, req)
+
+	var response HealthResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	if err != nil {
+		t.Errorf("Failed to decode response: %v", err)
+	}
+
+	if response.Metrics.TimedOutTasks != 1 {
+		t.Errorf("Expected 1 timed out task, got %d", response.Metrics.TimedOutTasks)
+	}
+
+	if response.Metrics.FailedTasks != 1 {
+		t.Errorf("Expected 1 failed task, got %d", response.Metrics.FailedTasks)
+	}
+}
+
+func TestHealthHandler_Health_PendingByPriority(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, 0)
+	handler := NewHealthHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "low-task", Priority: 1})
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "high-task-1", Priority: 7})
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "high-task-2", Priority: 7})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	handler.Health(w, req)
+
+	var response HealthResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	if err != nil {
+		t.Errorf("Failed to decode response: %v", err)
+	}
+
+	if response.Metrics.PendingByPriority[7] != 2 {
+		t.Errorf("Expected 2 pending tasks with priority 7, got %d", response.Metrics.PendingByPriority[7])
+	}
+
+	if response.Metrics.PendingByPriority[1] != 1 {
+		t.Errorf("Expected 1 pending task with priority 1, got %d", response.Metrics.PendingByPriority[1])
+	}
+}
+
+func TestHealthHandler_Health_QueueMetrics(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTestingWithOptions(repo, service.Options{
+		Workers: 1,
+		Queues:  map[string]int{"reports": 0},
+	})
+	handler := NewHealthHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+
+	manager.PauseQueue("reports")
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "report-1", Queue: "reports"})
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "report-2", Queue: "reports"})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+
+	handler.Health(w, req)
+
+	var response HealthResponse
+	err := json.NewDecoder(w.Body).Decode(&response)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	reports, exists := response.Metrics.Queues["reports"]
+	if !exists {
+		t.Fatalf("Expected metrics for queue 'reports', got %v", response.Metrics.Queues)
+	}
+
+	if reports.PendingTasks != 2 || !reports.Paused {
+		t.Errorf("Expected 2 pending tasks in paused queue, got %+v", reports)
+	}
+
+	if _, exists := response.Metrics.Queues["default"]; !exists {
+		t.Error("Expected metrics for queue 'default'")
+	}
+}
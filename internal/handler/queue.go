@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type QueueHandler struct {
+	taskManager *service.TaskManager
+	logger      *logrus.Logger
+}
+
+func NewQueueHandler(taskManager *service.TaskManager) *QueueHandler {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return &QueueHandler{
+		taskManager: taskManager,
+		logger:      logger,
+	}
+}
+
+func (h *QueueHandler) ListQueues(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.taskManager.GetQueues())
+}
+
+func (h *QueueHandler) PauseQueue(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, mux.Vars(r)["name"], true)
+}
+
+func (h *QueueHandler) ResumeQueue(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, mux.Vars(r)["name"], false)
+}
+
+func (h *QueueHandler) setPaused(w http.ResponseWriter, name string, paused bool) {
+	var err error
+	if paused {
+		err = h.taskManager.PauseQueue(name)
+	} else {
+		err = h.taskManager.ResumeQueue(name)
+	}
+
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, h.logger, "Queue not found", http.StatusNotFound)
+		} else {
+			writeError(w, h.logger, "Failed to update queue", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	for _, queue := range h.taskManager.GetQueues() {
+		if queue.Name == name {
+			writeJSON(w, http.StatusOK, queue)
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func setupTestQueueHandler() *QueueHandler {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTestingWithOptions(repo, service.Options{
+		Queues: map[string]int{"reports": 0},
+	})
+	handler := NewQueueHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+	return handler
+}
+
+func TestQueueHandler_PauseAndResume(t *testing.T) {
+	handler := setupTestQueueHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/queues/reports/pause", nil)
+	req = mux.SetURLVars(req, map[string]string{"name": "reports"})
+	w := httptest.NewRecorder()
+
+	handler.PauseQueue(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var queue service.QueueStats
+	err := json.NewDecoder(w.Body).Decode(&queue)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if !queue.Paused {
+		t.Error("Expected queue to be paused")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/queues/reports/resume", nil)
+	req = mux.SetURLVars(req, map[string]string{"name": "reports"})
+	w = httptest.NewRecorder()
+
+	handler.ResumeQueue(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestQueueHandler_PauseQueue_NotFound(t *testing.T) {
+	handler := setupTestQueueHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/queues/missing/pause", nil)
+	req = mux.SetURLVars(req, map[string]string{"name": "missing"})
+	w := httptest.NewRecorder()
+
+	handler.PauseQueue(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestQueueHandler_ListQueues(t *testing.T) {
+	handler := setupTestQueueHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/queues", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListQueues(w, req)
+
+	var queues []service.QueueStats
+	err := json.NewDecoder(w.Body).Decode(&queues)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(queues) != 2 {
+		t.Errorf("Expected 2 queues, got %d", len(queues))
+	}
+}
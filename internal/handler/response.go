@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, logger *logrus.Logger, message string, statusCode int) {
+	logger.WithFields(logrus.Fields{
+		"status_code": statusCode,
+		"message":     message,
+	}).Warn("Sending error response")
+
+	writeJSON(w, statusCode, ErrorResponse{Error: message})
+}
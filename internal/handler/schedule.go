@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type ScheduleHandler struct {
+	taskManager *service.TaskManager
+	logger      *logrus.Logger
+}
+
+func NewScheduleHandler(taskManager *service.TaskManager) *ScheduleHandler {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return &ScheduleHandler{
+		taskManager: taskManager,
+		logger:      logger,
+	}
+}
+
+type CreateScheduleRequest struct {
+	ID            string              `json:"id"`
+	Cron          string              `json:"cron"`
+	Timezone      string              `json:"timezone"`
+	Template      model.TaskTemplate  `json:"template"`
+	OverlapPolicy model.OverlapPolicy `json:"overlap_policy"`
+	Paused        bool                `json:"paused"`
+}
+
+func (h *ScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req CreateScheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.WithField("error", err.Error()).Warn("Invalid JSON in create schedule request")
+		writeError(w, h.logger, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.ID == "" {
+		h.logger.Warn("Empty schedule ID in create request")
+		writeError(w, h.logger, "Schedule ID is required", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.taskManager.CreateSchedule(service.ScheduleSpec{
+		ID:            req.ID,
+		Cron:          req.Cron,
+		Timezone:      req.Timezone,
+		Template:      req.Template,
+		OverlapPolicy: req.OverlapPolicy,
+		Paused:        req.Paused,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			writeError(w, h.logger, err.Error(), http.StatusConflict)
+		} else if errors.Is(err, service.ErrValidation) {
+			writeError(w, h.logger, err.Error(), http.StatusBadRequest)
+		} else {
+			writeError(w, h.logger, "Failed to create schedule", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, schedule)
+}
+
+func (h *ScheduleHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.taskManager.GetAllSchedules()
+	if err != nil {
+		writeError(w, h.logger, "Failed to get schedules", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedules)
+}
+
+func (h *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID := mux.Vars(r)["id"]
+
+	schedule, err := h.taskManager.GetSchedule(scheduleID)
+	if err != nil {
+		h.writeLookupError(w, err, "Failed to get schedule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID := mux.Vars(r)["id"]
+
+	err := h.taskManager.DeleteSchedule(scheduleID)
+	if err != nil {
+		h.writeLookupError(w, err, "Failed to delete schedule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ScheduleHandler) PauseSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID := mux.Vars(r)["id"]
+
+	schedule, err := h.taskManager.PauseSchedule(scheduleID)
+	if err != nil {
+		h.writeLookupError(w, err, "Failed to pause schedule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID := mux.Vars(r)["id"]
+
+	schedule, err := h.taskManager.ResumeSchedule(scheduleID)
+	if err != nil {
+		h.writeLookupError(w, err, "Failed to resume schedule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+func (h *ScheduleHandler) writeLookupError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrNotFound) {
+		writeError(w, h.logger, "Schedule not found", http.StatusNotFound)
+	} else {
+		writeError(w, h.logger, message, http.StatusInternalServerError)
+	}
+}
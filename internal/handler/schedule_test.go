@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func setupTestScheduleHandler() *ScheduleHandler {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, 0)
+	handler := NewScheduleHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+	return handler
+}
+
+func TestScheduleHandler_CreateAndGet(t *testing.T) {
+	handler := setupTestScheduleHandler()
+
+	body := `{"id": "nightly-report", "cron": "0 2 * * *", "timezone": "Europe/Moscow", "template": {"type": "default", "payload": {"report": "sales"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/schedules", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateSchedule(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/schedules/nightly-report", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "nightly-report"})
+	w = httptest.NewRecorder()
+
+	handler.GetSchedule(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var schedule model.Schedule
+	err := json.NewDecoder(w.Body).Decode(&schedule)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if schedule.Timezone != "Europe/Moscow" {
+		t.Errorf("Expected timezone 'Europe/Moscow', got '%s'", schedule.Timezone)
+	}
+
+	if schedule.NextRunAt == nil {
+		t.Error("Expected next_run_at to be set")
+	}
+}
+
+func TestScheduleHandler_CreateSchedule_InvalidCron(t *testing.T) {
+	handler := setupTestScheduleHandler()
+
+	body := `{"id": "broken", "cron": "every night"}`
+	req := httptest.NewRequest(http.MethodPost, "/schedules", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateSchedule(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestScheduleHandler_DeleteSchedule(t *testing.T) {
+	handler := setupTestScheduleHandler()
+
+	_, err := handler.taskManager.CreateSchedule(service.ScheduleSpec{ID: "hourly", Cron: "@hourly"})
+	if err != nil {
+		t.Fatalf("Failed to create schedule: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/schedules/hourly", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "hourly"})
+	w := httptest.NewRecorder()
+
+	handler.DeleteSchedule(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.DeleteSchedule(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d on repeated delete, got %d", http.StatusNotFound, w.Code)
+	}
+}
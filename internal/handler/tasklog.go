@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// GetTaskLogs возвращает страницу журнала задачи. С follow=true записи
+// отдаются потоком NDJSON, пока задача не завершится или клиент не отключится
+func (h *TaskHandler) GetTaskLogs(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	query, follow, err := parseLogQuery(r)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.taskManager.GetTaskLogs(taskID, query)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			h.writeError(w, "Task not found", http.StatusNotFound)
+		} else if errors.Is(err, service.ErrValidation) {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+		} else {
+			h.writeError(w, "Failed to get task logs", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if !follow {
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+
+	h.followTaskLogs(w, r, taskID, page)
+}
+
+func (h *TaskHandler) followTaskLogs(w http.ResponseWriter, r *http.Request, taskID string, page *service.TaskLogPage) {
+	// поток живет дольше WriteTimeout сервера
+	controller := http.NewResponseController(w)
+	controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for {
+		for _, entry := range page.Entries {
+			if err := encoder.Encode(entry); err != nil {
+				return
+			}
+		}
+		controller.Flush()
+
+		if page.Finished && !page.More {
+			return
+		}
+
+		if !page.More {
+			select {
+			case <-page.Updated():
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		var err error
+		page, err = h.taskManager.GetTaskLogs(taskID, service.TaskLogQuery{Offset: page.NextOffset})
+		if err != nil {
+			// задача удалена во время чтения
+			return
+		}
+	}
+}
+
+func parseLogQuery(r *http.Request) (service.TaskLogQuery, bool, error) {
+	var query service.TaskLogQuery
+	values := r.URL.Query()
+
+	for name, target := range map[string]*int{"limit": &query.Limit, "tail": &query.Tail} {
+		if raw := values.Get(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				return query, false, fmt.Errorf("%s must be a non-negative integer", name)
+			}
+			*target = value
+		}
+	}
+
+	if raw := values.Get("offset"); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value < 0 {
+			return query, false, fmt.Errorf("offset must be a non-negative integer")
+		}
+		query.Offset = value
+	}
+
+	follow := false
+	if raw := values.Get("follow"); raw != "" {
+		var err error
+		follow, err = strconv.ParseBool(raw)
+		if err != nil {
+			return query, false, fmt.Errorf("follow must be a boolean")
+		}
+	}
+
+	// в режиме follow limit не применяется
+	if follow {
+		query.Limit = 0
+	}
+
+	return query, follow, nil
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func setupTestLogHandler(executor service.Executor) *TaskHandler {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, 1)
+	manager.RegisterExecutor("chatty", executor)
+	handler := NewTaskHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+	return handler
+}
+
+func getTaskLogs(handler *TaskHandler, id, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/tasks/"+id+"/logs?"+query, nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id})
+	w := httptest.NewRecorder()
+	handler.GetTaskLogs(w, req)
+	return w
+}
+
+func TestTaskHandler_GetTaskLogs(t *testing.T) {
+	handler := setupTestLogHandler(func(ctx context.Context, task *model.Task) (any, error) {
+		for i := 0; i < 5; i++ {
+			service.TaskLoggerFromContext(ctx).Infof("step %d", i)
+		}
+		return "done", nil
+	})
+
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "chatty-1", Type: "chatty"})
+	for i := 0; i < 50; i++ {
+		task, _ := handler.taskManager.GetTask("chatty-1")
+		if task.IsCompleted() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	w := getTaskLogs(handler, "chatty-1", "offset=2&limit=3")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var page service.TaskLogPage
+	err := json.NewDecoder(w.Body).Decode(&page)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(page.Entries) != 3 || page.Entries[0].Offset != 2 || page.NextOffset != 5 || !page.More {
+		t.Errorf("Expected entries 2-4 with more, got %+v", page)
+	}
+
+	w = getTaskLogs(handler, "chatty-1", "tail=1")
+	json.NewDecoder(w.Body).Decode(&page)
+	if len(page.Entries) != 1 || page.Entries[0].Message != "task completed" || !page.Finished {
+		t.Errorf("Expected final entry of finished task, got %+v", page)
+	}
+
+	if w := getTaskLogs(handler, "chatty-1", "limit=-1"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for negative limit, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if w := getTaskLogs(handler, "missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for unknown task, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestTaskHandler_GetTaskLogs_Follow(t *testing.T) {
+	release := make(chan struct{})
+	handler := setupTestLogHandler(func(ctx context.Context, task *model.Task) (any, error) {
+		service.TaskLoggerFromContext(ctx).Infof("waiting for input")
+		<-release
+		service.TaskLoggerFromContext(ctx).Infof("input received")
+		return "done", nil
+	})
+
+	handler.taskManager.CreateTask(service.TaskSpec{ID: "chatty-2", Type: "chatty"})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- getTaskLogs(handler, "chatty-2", "follow=true")
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	var w *httptest.ResponseRecorder
+	select {
+	case w = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Follow did not finish after task completed")
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON content type, got %s", contentType)
+	}
+
+	var messages []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var entry model.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode entry %q: %v", scanner.Text(), err)
+		}
+		messages = append(messages, entry.Message)
+	}
+
+	if len(messages) == 0 || messages[len(messages)-1] != "task completed" {
+		t.Errorf("Expected stream to end with 'task completed', got %v", messages)
+	}
+}
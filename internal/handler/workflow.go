@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type WorkflowHandler struct {
+	taskManager *service.TaskManager
+	logger      *logrus.Logger
+}
+
+func NewWorkflowHandler(taskManager *service.TaskManager) *WorkflowHandler {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	return &WorkflowHandler{
+		taskManager: taskManager,
+		logger:      logger,
+	}
+}
+
+type CreateWorkflowRequest struct {
+	ID    string               `json:"id"`
+	Input json.RawMessage      `json:"input"`
+	Steps []model.WorkflowStep `json:"steps"`
+}
+
+func (h *WorkflowHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
+	var req CreateWorkflowRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		h.logger.WithField("error", err.Error()).Warn("Invalid JSON in create workflow request")
+		writeError(w, h.logger, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if req.ID == "" {
+		h.logger.Warn("Empty workflow ID in create request")
+		writeError(w, h.logger, "Workflow ID is required", http.StatusBadRequest)
+		return
+	}
+
+	workflow, err := h.taskManager.CreateWorkflow(service.WorkflowSpec{
+		ID:    req.ID,
+		Input: req.Input,
+		Steps: req.Steps,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			writeError(w, h.logger, err.Error(), http.StatusConflict)
+		} else if errors.Is(err, service.ErrValidation) {
+			writeError(w, h.logger, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, service.ErrShuttingDown) {
+			writeError(w, h.logger, "Service is shutting down", http.StatusServiceUnavailable)
+		} else {
+			writeError(w, h.logger, "Failed to create workflow", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, workflow)
+}
+
+func (h *WorkflowHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	workflowID := mux.Vars(r)["id"]
+
+	workflow, err := h.taskManager.GetWorkflow(workflowID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, h.logger, "Workflow not found", http.StatusNotFound)
+		} else {
+			writeError(w, h.logger, "Failed to get workflow", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, workflow)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+	"github.com/bambutcha/taskflow/internal/service"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func setupTestWorkflowHandler() *WorkflowHandler {
+	repo := repository.NewMemoryRepository()
+	manager := service.NewTaskManagerForTesting(repo, 0)
+	handler := NewWorkflowHandler(manager)
+	handler.logger.SetLevel(logrus.PanicLevel)
+	return handler
+}
+
+func TestWorkflowHandler_CreateAndGet(t *testing.T) {
+	handler := setupTestWorkflowHandler()
+
+	body := `{"id": "etl", "input": {"source": "s3"}, "steps": [{"name": "extract"}, {"name": "load"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/workflows", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateWorkflow(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/workflows/etl", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "etl"})
+	w = httptest.NewRecorder()
+
+	handler.GetWorkflow(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var workflow model.Workflow
+	err := json.NewDecoder(w.Body).Decode(&workflow)
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if workflow.Status != model.WorkflowRunning {
+		t.Errorf("Expected status %s, got %s", model.WorkflowRunning, workflow.Status)
+	}
+
+	if len(workflow.CurrentSteps) != 1 || workflow.CurrentSteps[0] != "extract" {
+		t.Errorf("Expected current steps [extract], got %v", workflow.CurrentSteps)
+	}
+
+	if workflow.StepStates[0].TaskID != "etl.extract" {
+		t.Errorf("Expected first step task 'etl.extract', got '%s'", workflow.StepStates[0].TaskID)
+	}
+}
+
+func TestWorkflowHandler_CreateWorkflow_Invalid(t *testing.T) {
+	handler := setupTestWorkflowHandler()
+
+	body := `{"id": "broken", "steps": []}`
+	req := httptest.NewRequest(http.MethodPost, "/workflows", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateWorkflow(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestWorkflowHandler_GetWorkflow_NotFound(t *testing.T) {
+	handler := setupTestWorkflowHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/missing", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "missing"})
+	w := httptest.NewRecorder()
+
+	handler.GetWorkflow(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
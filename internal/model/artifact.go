@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Artifact описывает именованный файл, который исполнитель приложил к задаче
+type Artifact struct {
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type BatchState string
+
+const (
+	BatchRunning        BatchState = "running"
+	BatchSucceeded      BatchState = "succeeded"
+	BatchPartialFailure BatchState = "partial_failure"
+	BatchFailed         BatchState = "failed"
+)
+
+// Batch объединяет задачи, созданные одним запросом. Progress - доля
+// завершенных задач в процентах, OnComplete запускается после завершения всех задач.
+// Удаленные задачи считаются завершенными неуспешно
+type Batch struct {
+	ID             string             `json:"id"`
+	TaskIDs        []string           `json:"task_ids"`
+	DeletedTaskIDs []string           `json:"deleted_task_ids,omitempty"`
+	Counts         map[TaskStatus]int `json:"counts"`
+	Progress       float64            `json:"progress"`
+	State          BatchState         `json:"state"`
+	OnComplete     *TaskTemplate      `json:"on_complete,omitempty"`
+	CallbackTaskID string             `json:"callback_task_id,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	CompletedAt    *time.Time         `json:"completed_at,omitempty"`
+}
+
+func (b *Batch) IsCompleted() bool {
+	return b.State != BatchRunning
+}
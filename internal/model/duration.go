@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration сериализуется в JSON как строка вида "1m30s",
+// при разборе также принимает число секунд
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(v * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		*d = Duration(parsed)
+	case nil:
+		*d = 0
+	default:
+		return fmt.Errorf("invalid duration: %s", string(data))
+	}
+
+	return nil
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
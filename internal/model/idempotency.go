@@ -0,0 +1,17 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyRecord запоминает запрос, выполненный с заголовком Idempotency-Key,
+// и ответ на него, чтобы повтор запроса вернул тот же результат
+type IdempotencyRecord struct {
+	Key         string          `json:"key"`
+	Fingerprint string          `json:"fingerprint"`
+	TaskID      string          `json:"task_id"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
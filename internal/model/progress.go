@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Progress - последний отчет исполнителя о ходе выполнения задачи
+type Progress struct {
+	Percent   float64   `json:"percent"`
+	Message   string    `json:"message,omitempty"`
+	Step      string    `json:"step,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// RetryPolicy задает повторы задачи. Jitter - указатель, чтобы отличать
+// незаданное значение от явного 0, отключающего случайное отклонение
+type RetryPolicy struct {
+	MaxAttempts    int      `json:"max_attempts"`
+	InitialBackoff Duration `json:"initial_backoff"`
+	Multiplier     float64  `json:"multiplier"`
+	MaxBackoff     Duration `json:"max_backoff"`
+	Jitter         *float64 `json:"jitter,omitempty"`
+}
+
+type Attempt struct {
+	Number     int        `json:"number"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OverlapPolicy string
+
+const (
+	OverlapSkip  OverlapPolicy = "skip"
+	OverlapQueue OverlapPolicy = "queue"
+	OverlapAllow OverlapPolicy = "allow"
+)
+
+// TaskTemplate описывает задачу, которая создается по расписанию
+type TaskTemplate struct {
+	Type             string          `json:"type"`
+	Payload          json.RawMessage `json:"payload,omitempty"`
+	Queue            string          `json:"queue,omitempty"`
+	Priority         int             `json:"priority"`
+	ConcurrencyKey   string          `json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int             `json:"concurrency_limit,omitempty"`
+	Timeout          Duration        `json:"timeout,omitempty"`
+	RetryPolicy      *RetryPolicy    `json:"retry_policy,omitempty"`
+}
+
+type Schedule struct {
+	ID            string        `json:"id"`
+	Cron          string        `json:"cron"`
+	Timezone      string        `json:"timezone"`
+	Template      TaskTemplate  `json:"template"`
+	OverlapPolicy OverlapPolicy `json:"overlap_policy"`
+	Paused        bool          `json:"paused"`
+	CreatedAt     time.Time     `json:"created_at"`
+	NextRunAt     *time.Time    `json:"next_run_at,omitempty"`
+	LastRunAt     *time.Time    `json:"last_run_at,omitempty"`
+	LastTaskID    string        `json:"last_task_id,omitempty"`
+	QueuedRuns    []time.Time   `json:"queued_runs,omitempty"`
+}
@@ -1,24 +1,67 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type TaskStatus string
 
 const (
+	StatusBlocked   TaskStatus = "blocked"
+	StatusScheduled TaskStatus = "scheduled"
 	StatusPending   TaskStatus = "pending"
 	StatusRunning   TaskStatus = "running"
 	StatusCompleted TaskStatus = "completed"
 	StatusFailed    TaskStatus = "failed"
+	StatusCancelled TaskStatus = "cancelled"
+)
+
+const (
+	MinPriority = 0
+	MaxPriority = 9
+)
+
+const (
+	ErrorCodeDeadlineExceeded = "deadline_exceeded"
+	ErrorCodeDependencyFailed = "dependency_failed"
+	ErrorCodeResultTooLarge   = "result_too_large"
+	ErrorCodeLeaseExpired     = "lease_expired"
+	ErrorCodeInterrupted      = "interrupted"
 )
 
 type Task struct {
-	ID          string     `json:"id"`
-	Status      TaskStatus `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	StartedAt   *time.Time `json:"started_at,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	Result      string     `json:"result,omitempty"`
-	Error       string     `json:"error,omitempty"`
+	ID               string          `json:"id"`
+	Type             string          `json:"type"`
+	Payload          json.RawMessage `json:"payload,omitempty"`
+	Status           TaskStatus      `json:"status"`
+	Queue            string          `json:"queue"`
+	Priority         int             `json:"priority"`
+	ConcurrencyKey   string          `json:"concurrency_key,omitempty"`
+	ConcurrencyLimit int             `json:"concurrency_limit,omitempty"`
+	Timeout          Duration        `json:"timeout,omitempty"`
+	RetryPolicy      *RetryPolicy    `json:"retry_policy,omitempty"`
+	Attempt          int             `json:"attempt"`
+	NextRetryAt      *time.Time      `json:"next_retry_at,omitempty"`
+	Attempts         []Attempt       `json:"attempts,omitempty"`
+	DependsOn        []string        `json:"depends_on,omitempty"`
+	Dependents       []string        `json:"dependents,omitempty"`
+	ScheduleID       string          `json:"schedule_id,omitempty"`
+	WorkflowID       string          `json:"workflow_id,omitempty"`
+	WorkflowStep     string          `json:"workflow_step,omitempty"`
+	BatchID          string          `json:"batch_id,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	RunAt            *time.Time      `json:"run_at,omitempty"`
+	StartedAt        *time.Time      `json:"started_at,omitempty"`
+	LastHeartbeatAt  *time.Time      `json:"last_heartbeat_at,omitempty"`
+	LeaseExpiresAt   *time.Time      `json:"lease_expires_at,omitempty"`
+	CompletedAt      *time.Time      `json:"completed_at,omitempty"`
+	CancelledAt      *time.Time      `json:"cancelled_at,omitempty"`
+	Progress         *Progress       `json:"progress,omitempty"`
+	Artifacts        []Artifact      `json:"artifacts,omitempty"`
+	Result           json.RawMessage `json:"result,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	ErrorCode        string          `json:"error_code,omitempty"`
 }
 
 func NewTask(id string) *Task {
@@ -30,9 +73,13 @@ func NewTask(id string) *Task {
 }
 
 func (t *Task) IsCompleted() bool {
-	return t.Status == StatusCompleted || t.Status == StatusFailed
+	return t.Status == StatusCompleted || t.Status == StatusFailed || t.Status == StatusCancelled
 }
 
 func (t *Task) IsRunning() bool {
 	return t.Status == StatusRunning
 }
+
+func (t *Task) IsPending() bool {
+	return t.Status == StatusPending
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	LogSourceSystem   = "system"
+	LogSourceExecutor = "executor"
+)
+
+const (
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// LogEntry - запись журнала задачи. Offset растет на единицу с каждой записью
+// и не меняется, когда старые записи вытесняются из буфера
+type LogEntry struct {
+	Offset  int64     `json:"offset"`
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Source  string    `json:"source"`
+	Message string    `json:"message"`
+}
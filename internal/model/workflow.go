@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WorkflowStatus string
+
+const (
+	WorkflowRunning   WorkflowStatus = "running"
+	WorkflowCompleted WorkflowStatus = "completed"
+	WorkflowFailed    WorkflowStatus = "failed"
+	WorkflowCancelled WorkflowStatus = "cancelled"
+)
+
+// WorkflowStep описывает шаг конвейера. Если DependsOn не задан,
+// шаг зависит от предыдущего в списке
+type WorkflowStep struct {
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`
+	DependsOn   []string     `json:"depends_on,omitempty"`
+	Queue       string       `json:"queue,omitempty"`
+	Priority    int          `json:"priority"`
+	Timeout     Duration     `json:"timeout,omitempty"`
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+}
+
+type StepState struct {
+	Name        string          `json:"name"`
+	TaskID      string          `json:"task_id,omitempty"`
+	Status      TaskStatus      `json:"status,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+type Workflow struct {
+	ID           string          `json:"id"`
+	Input        json.RawMessage `json:"input,omitempty"`
+	Steps        []WorkflowStep  `json:"steps"`
+	Status       WorkflowStatus  `json:"status"`
+	CurrentSteps []string        `json:"current_steps,omitempty"`
+	StepStates   []StepState     `json:"step_states"`
+	Error        string          `json:"error,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+}
+
+func (w *Workflow) IsCompleted() bool {
+	return w.Status == WorkflowCompleted || w.Status == WorkflowFailed || w.Status == WorkflowCancelled
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ArtifactStore определяет интерфейс для хранения содержимого артефактов задач
+type ArtifactStore interface {
+	Save(taskID, name string, content io.Reader) (int64, error)
+	Open(taskID, name string) (io.ReadCloser, error)
+	DeleteAll(taskID string) error
+}
+
+// FileArtifactStore хранит артефакты в каталоге: по подкаталогу на задачу
+type FileArtifactStore struct {
+	dir string
+}
+
+// NewFileArtifactStore создает хранилище артефактов в каталоге dir
+func NewFileArtifactStore(dir string) *FileArtifactStore {
+	return &FileArtifactStore{dir: dir}
+}
+
+// Save записывает артефакт через временный файл, чтобы читатели
+// не увидели частично записанное содержимое
+func (s *FileArtifactStore) Save(taskID, name string, content io.Reader) (int64, error) {
+	dir := s.taskDir(taskID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create artifact file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	size, err := io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write artifact %s: %w", name, err)
+	}
+
+	if err := os.Rename(file.Name(), filepath.Join(dir, name)); err != nil {
+		return 0, fmt.Errorf("failed to save artifact %s: %w", name, err)
+	}
+
+	return size, nil
+}
+
+// Open открывает артефакт для чтения
+func (s *FileArtifactStore) Open(taskID, name string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(s.taskDir(taskID), name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("artifact %s %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact %s: %w", name, err)
+	}
+	return file, nil
+}
+
+// DeleteAll удаляет все артефакты задачи
+func (s *FileArtifactStore) DeleteAll(taskID string) error {
+	return os.RemoveAll(s.taskDir(taskID))
+}
+
+// taskDir кодирует ID задачи, чтобы он не мог выйти за пределы каталога
+func (s *FileArtifactStore) taskDir(taskID string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(taskID)))
+}
+
+// MemoryArtifactStore реализует ArtifactStore с хранением в памяти
+type MemoryArtifactStore struct {
+	artifacts map[string]map[string][]byte
+	mutex     sync.RWMutex
+}
+
+// NewMemoryArtifactStore создает новый экземпляр хранилища артефактов
+func NewMemoryArtifactStore() *MemoryArtifactStore {
+	return &MemoryArtifactStore{
+		artifacts: make(map[string]map[string][]byte),
+	}
+}
+
+// Save сохраняет артефакт, заменяя одноименный
+func (s *MemoryArtifactStore) Save(taskID, name string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.artifacts[taskID] == nil {
+		s.artifacts[taskID] = make(map[string][]byte)
+	}
+	s.artifacts[taskID][name] = data
+	return int64(len(data)), nil
+}
+
+// Open возвращает содержимое артефакта
+func (s *MemoryArtifactStore) Open(taskID, name string) (io.ReadCloser, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data, exists := s.artifacts[taskID][name]
+	if !exists {
+		return nil, fmt.Errorf("artifact %s %w", name, ErrNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// DeleteAll удаляет все артефакты задачи
+func (s *MemoryArtifactStore) DeleteAll(taskID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.artifacts, taskID)
+	return nil
+}
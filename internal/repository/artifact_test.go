@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func testArtifactStore(t *testing.T, store ArtifactStore) {
+	size, err := store.Save("../task-1", "report.csv", strings.NewReader("id,total\n1,42\n"))
+	if err != nil {
+		t.Fatalf("Save() error = %v, want nil", err)
+	}
+	if size != 14 {
+		t.Errorf("Save() size = %d, want 14", size)
+	}
+
+	// Повторное сохранение заменяет содержимое
+	store.Save("../task-1", "report.csv", strings.NewReader("id,total\n"))
+
+	content, err := store.Open("../task-1", "report.csv")
+	if err != nil {
+		t.Fatalf("Open() error = %v, want nil", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+
+	if string(data) != "id,total\n" {
+		t.Errorf("Open() content = %q, want replaced content", data)
+	}
+
+	if _, err := store.Open("../task-1", "missing.csv"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Open() error = %v, want not found", err)
+	}
+
+	err = store.DeleteAll("../task-1")
+	if err != nil {
+		t.Errorf("DeleteAll() error = %v, want nil", err)
+	}
+
+	if _, err := store.Open("../task-1", "report.csv"); err == nil {
+		t.Error("Open() after DeleteAll() error = nil, want not found")
+	}
+}
+
+func TestMemoryArtifactStore(t *testing.T) {
+	testArtifactStore(t, NewMemoryArtifactStore())
+}
+
+func TestFileArtifactStore(t *testing.T) {
+	root := t.TempDir()
+	dir := root + "/artifacts"
+	testArtifactStore(t, NewFileArtifactStore(dir))
+
+	// ID задачи с ".." не должен выводить файлы за пределы каталога
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 1 || entries[0].Name() != "artifacts" {
+		t.Errorf("Files outside artifact directory: %v", entries)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// BatchRepository определяет интерфейс для работы с группами задач
+type BatchRepository interface {
+	Create(batch *model.Batch) error
+	GetByID(id string) (*model.Batch, error)
+	Update(batch *model.Batch) error
+	Delete(id string) error
+	GetAll() ([]*model.Batch, error)
+}
+
+// MemoryBatchRepository реализует BatchRepository с хранением в памяти
+type MemoryBatchRepository struct {
+	batches map[string]*model.Batch
+	mutex   sync.RWMutex
+}
+
+// NewMemoryBatchRepository создает новый экземпляр репозитория групп задач
+func NewMemoryBatchRepository() *MemoryBatchRepository {
+	return &MemoryBatchRepository{
+		batches: make(map[string]*model.Batch),
+	}
+}
+
+// Create добавляет новую группу задач в хранилище
+func (r *MemoryBatchRepository) Create(batch *model.Batch) error {
+	if batch == nil {
+		return fmt.Errorf("batch cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.batches[batch.ID]; exists {
+		return fmt.Errorf("batch with ID %s %w", batch.ID, ErrAlreadyExists)
+	}
+
+	r.batches[batch.ID] = batch
+	return nil
+}
+
+// GetByID возвращает группу задач по ID
+func (r *MemoryBatchRepository) GetByID(id string) (*model.Batch, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	batch, exists := r.batches[id]
+	if !exists {
+		return nil, fmt.Errorf("batch with ID %s %w", id, ErrNotFound)
+	}
+
+	return batch, nil
+}
+
+// Update обновляет существующую группу задач
+func (r *MemoryBatchRepository) Update(batch *model.Batch) error {
+	if batch == nil {
+		return fmt.Errorf("batch cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.batches[batch.ID]; !exists {
+		return fmt.Errorf("batch with ID %s %w", batch.ID, ErrNotFound)
+	}
+
+	r.batches[batch.ID] = batch
+	return nil
+}
+
+// Delete удаляет группу задач из хранилища
+func (r *MemoryBatchRepository) Delete(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.batches[id]; !exists {
+		return fmt.Errorf("batch with ID %s %w", id, ErrNotFound)
+	}
+
+	delete(r.batches, id)
+	return nil
+}
+
+// GetAll возвращает все группы задач
+func (r *MemoryBatchRepository) GetAll() ([]*model.Batch, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	batches := make([]*model.Batch, 0, len(r.batches))
+	for _, batch := range r.batches {
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "errors"
+
+// Ошибки хранилищ; вызывающий код проверяет их через errors.Is
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// IdempotencyRepository определяет интерфейс для хранения ключей идемпотентности
+type IdempotencyRepository interface {
+	Save(record *model.IdempotencyRecord) error
+	Get(key string, now time.Time) (*model.IdempotencyRecord, error)
+	DeleteExpired(now time.Time) (int, error)
+}
+
+// MemoryIdempotencyRepository реализует IdempotencyRepository с хранением в памяти
+type MemoryIdempotencyRepository struct {
+	records map[string]*model.IdempotencyRecord
+	mutex   sync.RWMutex
+}
+
+// NewMemoryIdempotencyRepository создает новый экземпляр хранилища ключей идемпотентности
+func NewMemoryIdempotencyRepository() *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{
+		records: make(map[string]*model.IdempotencyRecord),
+	}
+}
+
+// Save сохраняет запись, заменяя истекшую запись с тем же ключом
+func (r *MemoryIdempotencyRepository) Save(record *model.IdempotencyRecord) error {
+	if record == nil {
+		return fmt.Errorf("idempotency record cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, exists := r.records[record.Key]; exists && existing.ExpiresAt.After(record.CreatedAt) {
+		return fmt.Errorf("idempotency key %s %w", record.Key, ErrAlreadyExists)
+	}
+
+	r.records[record.Key] = record
+	return nil
+}
+
+// Get возвращает действующую на момент now запись по ключу
+func (r *MemoryIdempotencyRepository) Get(key string, now time.Time) (*model.IdempotencyRecord, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	record, exists := r.records[key]
+	if !exists || !record.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("idempotency key %s %w", key, ErrNotFound)
+	}
+
+	return record, nil
+}
+
+// DeleteExpired удаляет истекшие записи и возвращает их количество
+func (r *MemoryIdempotencyRepository) DeleteExpired(now time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleted := 0
+	for key, record := range r.records {
+		if !record.ExpiresAt.After(now) {
+			delete(r.records, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
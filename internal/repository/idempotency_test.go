@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+func TestMemoryIdempotencyRepository(t *testing.T) {
+	repo := NewMemoryIdempotencyRepository()
+	now := time.Now()
+
+	record := &model.IdempotencyRecord{Key: "key-1", TaskID: "task-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	err := repo.Save(record)
+	if err != nil {
+		t.Errorf("Save() error = %v, want nil", err)
+	}
+
+	// Повторное сохранение действующего ключа должно вернуть ошибку
+	err = repo.Save(&model.IdempotencyRecord{Key: "key-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	if err == nil {
+		t.Error("Second Save() error = nil, want error")
+	}
+
+	saved, err := repo.Get("key-1", now)
+	if err != nil {
+		t.Errorf("Get() error = %v, want nil", err)
+	}
+
+	if saved.TaskID != "task-1" {
+		t.Errorf("Get() TaskID = %s, want task-1", saved.TaskID)
+	}
+
+	// После окна хранения ключ не находится и может быть использован заново
+	later := now.Add(2 * time.Hour)
+	if _, err := repo.Get("key-1", later); err == nil {
+		t.Error("Get() error = nil for expired key, want error")
+	}
+
+	deleted, _ := repo.DeleteExpired(later)
+	if deleted != 1 {
+		t.Errorf("DeleteExpired() = %d, want 1", deleted)
+	}
+}
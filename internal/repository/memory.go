@@ -39,7 +39,7 @@ func (r *MemoryRepository) Create(task *model.Task) error {
 	defer r.mutex.Unlock()
 
 	if _, exists := r.tasks[task.ID]; exists {
-		return fmt.Errorf("task with ID %s already exists", task.ID)
+		return fmt.Errorf("task with ID %s %w", task.ID, ErrAlreadyExists)
 	}
 
 	r.tasks[task.ID] = task
@@ -53,7 +53,7 @@ func (r *MemoryRepository) GetByID(id string) (*model.Task, error) {
 
 	task, exists := r.tasks[id]
 	if !exists {
-		return nil, fmt.Errorf("task with ID %s not found", id)
+		return nil, fmt.Errorf("task with ID %s %w", id, ErrNotFound)
 	}
 
 	return task, nil
@@ -69,7 +69,7 @@ func (r *MemoryRepository) Update(task *model.Task) error {
 	defer r.mutex.Unlock()
 
 	if _, exists := r.tasks[task.ID]; !exists {
-		return fmt.Errorf("task with ID %s not found", task.ID)
+		return fmt.Errorf("task with ID %s %w", task.ID, ErrNotFound)
 	}
 
 	r.tasks[task.ID] = task
@@ -82,7 +82,7 @@ func (r *MemoryRepository) Delete(id string) error {
 	defer r.mutex.Unlock()
 
 	if _, exists := r.tasks[id]; !exists {
-		return fmt.Errorf("task with ID %s not found", id)
+		return fmt.Errorf("task with ID %s %w", id, ErrNotFound)
 	}
 
 	delete(r.tasks, id)
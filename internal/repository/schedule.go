@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// ScheduleRepository определяет интерфейс для работы с расписаниями
+type ScheduleRepository interface {
+	Create(schedule *model.Schedule) error
+	GetByID(id string) (*model.Schedule, error)
+	Update(schedule *model.Schedule) error
+	Delete(id string) error
+	GetAll() ([]*model.Schedule, error)
+}
+
+// MemoryScheduleRepository реализует ScheduleRepository с хранением в памяти
+type MemoryScheduleRepository struct {
+	schedules map[string]*model.Schedule
+	mutex     sync.RWMutex
+}
+
+// NewMemoryScheduleRepository создает новый экземпляр репозитория расписаний
+func NewMemoryScheduleRepository() *MemoryScheduleRepository {
+	return &MemoryScheduleRepository{
+		schedules: make(map[string]*model.Schedule),
+	}
+}
+
+// Create добавляет новое расписание в хранилище
+func (r *MemoryScheduleRepository) Create(schedule *model.Schedule) error {
+	if schedule == nil {
+		return fmt.Errorf("schedule cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.schedules[schedule.ID]; exists {
+		return fmt.Errorf("schedule with ID %s %w", schedule.ID, ErrAlreadyExists)
+	}
+
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+// GetByID возвращает расписание по ID
+func (r *MemoryScheduleRepository) GetByID(id string) (*model.Schedule, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	schedule, exists := r.schedules[id]
+	if !exists {
+		return nil, fmt.Errorf("schedule with ID %s %w", id, ErrNotFound)
+	}
+
+	return schedule, nil
+}
+
+// Update обновляет существующее расписание
+func (r *MemoryScheduleRepository) Update(schedule *model.Schedule) error {
+	if schedule == nil {
+		return fmt.Errorf("schedule cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.schedules[schedule.ID]; !exists {
+		return fmt.Errorf("schedule with ID %s %w", schedule.ID, ErrNotFound)
+	}
+
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+// Delete удаляет расписание из хранилища
+func (r *MemoryScheduleRepository) Delete(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.schedules[id]; !exists {
+		return fmt.Errorf("schedule with ID %s %w", id, ErrNotFound)
+	}
+
+	delete(r.schedules, id)
+	return nil
+}
+
+// GetAll возвращает все расписания
+func (r *MemoryScheduleRepository) GetAll() ([]*model.Schedule, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	schedules := make([]*model.Schedule, 0, len(r.schedules))
+	for _, schedule := range r.schedules {
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
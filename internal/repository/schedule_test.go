@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+func TestMemoryScheduleRepository_CRUD(t *testing.T) {
+	repo := NewMemoryScheduleRepository()
+	schedule := &model.Schedule{ID: "nightly", Cron: "0 2 * * *"}
+
+	err := repo.Create(schedule)
+	if err != nil {
+		t.Errorf("Create() error = %v, want nil", err)
+	}
+
+	// Повторное создание должно вернуть ошибку
+	err = repo.Create(schedule)
+	if err == nil {
+		t.Error("Second Create() error = nil, want error")
+	}
+
+	schedule.Paused = true
+	err = repo.Update(schedule)
+	if err != nil {
+		t.Errorf("Update() error = %v, want nil", err)
+	}
+
+	saved, err := repo.GetByID("nightly")
+	if err != nil {
+		t.Errorf("GetByID() error = %v, want nil", err)
+	}
+
+	if !saved.Paused {
+		t.Error("GetByID() Paused = false, want true")
+	}
+
+	schedules, _ := repo.GetAll()
+	if len(schedules) != 1 {
+		t.Errorf("GetAll() length = %v, want 1", len(schedules))
+	}
+
+	err = repo.Delete("nightly")
+	if err != nil {
+		t.Errorf("Delete() error = %v, want nil", err)
+	}
+
+	_, err = repo.GetByID("nightly")
+	if err == nil {
+		t.Error("GetByID() after delete error = nil, want error")
+	}
+}
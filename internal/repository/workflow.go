@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// WorkflowRepository определяет интерфейс для работы с рабочими процессами
+type WorkflowRepository interface {
+	Create(workflow *model.Workflow) error
+	GetByID(id string) (*model.Workflow, error)
+	Update(workflow *model.Workflow) error
+	Delete(id string) error
+	GetAll() ([]*model.Workflow, error)
+}
+
+// MemoryWorkflowRepository реализует WorkflowRepository с хранением в памяти
+type MemoryWorkflowRepository struct {
+	workflows map[string]*model.Workflow
+	mutex     sync.RWMutex
+}
+
+// NewMemoryWorkflowRepository создает новый экземпляр репозитория рабочих процессов
+func NewMemoryWorkflowRepository() *MemoryWorkflowRepository {
+	return &MemoryWorkflowRepository{
+		workflows: make(map[string]*model.Workflow),
+	}
+}
+
+// Create добавляет новый рабочий процесс в хранилище
+func (r *MemoryWorkflowRepository) Create(workflow *model.Workflow) error {
+	if workflow == nil {
+		return fmt.Errorf("workflow cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.workflows[workflow.ID]; exists {
+		return fmt.Errorf("workflow with ID %s %w", workflow.ID, ErrAlreadyExists)
+	}
+
+	r.workflows[workflow.ID] = workflow
+	return nil
+}
+
+// GetByID возвращает рабочий процесс по ID
+func (r *MemoryWorkflowRepository) GetByID(id string) (*model.Workflow, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	workflow, exists := r.workflows[id]
+	if !exists {
+		return nil, fmt.Errorf("workflow with ID %s %w", id, ErrNotFound)
+	}
+
+	return workflow, nil
+}
+
+// Update обновляет существующий рабочий процесс
+func (r *MemoryWorkflowRepository) Update(workflow *model.Workflow) error {
+	if workflow == nil {
+		return fmt.Errorf("workflow cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.workflows[workflow.ID]; !exists {
+		return fmt.Errorf("workflow with ID %s %w", workflow.ID, ErrNotFound)
+	}
+
+	r.workflows[workflow.ID] = workflow
+	return nil
+}
+
+// Delete удаляет рабочий процесс из хранилища
+func (r *MemoryWorkflowRepository) Delete(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.workflows[id]; !exists {
+		return fmt.Errorf("workflow with ID %s %w", id, ErrNotFound)
+	}
+
+	delete(r.workflows, id)
+	return nil
+}
+
+// GetAll возвращает все рабочие процессы
+func (r *MemoryWorkflowRepository) GetAll() ([]*model.Workflow, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	workflows := make([]*model.Workflow, 0, len(r.workflows))
+	for _, workflow := range r.workflows {
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
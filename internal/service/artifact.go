@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const maxArtifactName = 255
+
+// Artifacts передается исполнителю через контекст и прикладывает
+// к выполняемой задаче файлы с результатами
+type Artifacts struct {
+	tm      *TaskManager
+	task    *model.Task
+	attempt int
+}
+
+type artifactsKey struct{}
+
+// ArtifactsFromContext возвращает артефакты выполняемой задачи или nil вне TaskManager
+func ArtifactsFromContext(ctx context.Context) *Artifacts {
+	artifacts, _ := ctx.Value(artifactsKey{}).(*Artifacts)
+	return artifacts
+}
+
+// Attach сохраняет артефакт и добавляет его в задачу. Артефакт с тем же
+// именем заменяется, например при повторной попытке. Попытка, которую уже
+// забрал reaper или Shutdown, артефакты прикладывать не может
+func (a *Artifacts) Attach(name, contentType string, content io.Reader) error {
+	if a == nil {
+		return fmt.Errorf("artifacts are available only to running tasks")
+	}
+	return a.tm.attachArtifact(a.task, a.attempt, name, contentType, content)
+}
+
+func (tm *TaskManager) attachArtifact(task *model.Task, attempt int, name, contentType string, content io.Reader) error {
+	if err := validateArtifactName(name); err != nil {
+		return err
+	}
+
+	// проверка до записи, чтобы старая попытка не перезаписала файл текущей
+	tm.mutex.Lock()
+	current := attemptCurrent(task, attempt)
+	tm.mutex.Unlock()
+	if !current {
+		return staleAttemptError(task, attempt)
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	size, err := tm.artifacts.Save(task.ID, name, content)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"task_id":  task.ID,
+			"artifact": name,
+			"error":    err.Error(),
+		}).Error("Failed to save artifact")
+		return err
+	}
+
+	artifact := model.Artifact{
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   time.Now(),
+	}
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	// задачу могли удалить, пока исполнитель писал артефакт
+	if _, err := tm.repo.GetByID(task.ID); err != nil {
+		tm.artifacts.DeleteAll(task.ID)
+		return fmt.Errorf("%w: %w", ErrTaskNotFound, err)
+	}
+
+	if !attemptCurrent(task, attempt) {
+		return staleAttemptError(task, attempt)
+	}
+
+	replaced := false
+	for i := range task.Artifacts {
+		if task.Artifacts[i].Name == name {
+			task.Artifacts[i] = artifact
+			replaced = true
+		}
+	}
+	if !replaced {
+		task.Artifacts = append(task.Artifacts, artifact)
+	}
+
+	err = tm.repo.Update(task)
+	if err != nil {
+		return err
+	}
+
+	tm.logEvent(task, model.LogLevelInfo, "artifact %s attached (%d bytes)", name, size)
+	tm.logger.WithFields(logrus.Fields{
+		"task_id":  task.ID,
+		"artifact": name,
+		"size":     size,
+	}).Info("Artifact attached")
+	return nil
+}
+
+// OpenArtifact возвращает описание артефакта и его содержимое; вызывающий закрывает reader
+func (tm *TaskManager) OpenArtifact(taskID, name string) (model.Artifact, io.ReadCloser, error) {
+	task, err := tm.repo.GetByID(taskID)
+	if err != nil {
+		return model.Artifact{}, nil, fmt.Errorf("%w: %w", ErrTaskNotFound, err)
+	}
+
+	tm.mutex.Lock()
+	var artifact *model.Artifact
+	for i := range task.Artifacts {
+		if task.Artifacts[i].Name == name {
+			found := task.Artifacts[i]
+			artifact = &found
+		}
+	}
+	tm.mutex.Unlock()
+
+	if artifact == nil {
+		return model.Artifact{}, nil, fmt.Errorf("artifact %s %w", name, ErrNotFound)
+	}
+
+	content, err := tm.artifacts.Open(taskID, name)
+	if err != nil {
+		return model.Artifact{}, nil, err
+	}
+	return *artifact, content, nil
+}
+
+func (tm *TaskManager) deleteArtifacts(task *model.Task) {
+	err := tm.artifacts.DeleteAll(task.ID)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": task.ID,
+			"error":   err.Error(),
+		}).Error("Failed to delete task artifacts")
+	}
+}
+
+func validateArtifactName(name string) error {
+	if name == "" || len(name) > maxArtifactName {
+		return validationErrorf("invalid artifact name: must be 1-%d characters", maxArtifactName)
+	}
+
+	if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/\\\x00") {
+		return validationErrorf("invalid artifact name: %q must not start with a dot or contain path separators", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_Artifacts(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	store := repository.NewMemoryArtifactStore()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{Workers: 1, Artifacts: store})
+
+	manager.RegisterExecutor("export", func(ctx context.Context, task *model.Task) (any, error) {
+		artifacts := ArtifactsFromContext(ctx)
+		if err := artifacts.Attach("../escape", "", strings.NewReader("x")); err == nil {
+			t.Error("Attach() error = nil for path name, want invalid artifact name")
+		}
+		return "exported", artifacts.Attach("customers.csv", "text/csv", strings.NewReader("id\n1\n"))
+	})
+
+	manager.CreateTask(TaskSpec{ID: "export-1", Type: "export"})
+	task := waitForStatus(t, manager, "export-1", model.StatusCompleted)
+
+	if len(task.Artifacts) != 1 || task.Artifacts[0].Name != "customers.csv" || task.Artifacts[0].Size != 5 {
+		t.Fatalf("Artifacts = %+v, want customers.csv of 5 bytes", task.Artifacts)
+	}
+
+	artifact, content, err := manager.OpenArtifact("export-1", "customers.csv")
+	if err != nil {
+		t.Fatalf("OpenArtifact() error = %v, want nil", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+
+	if artifact.ContentType != "text/csv" || string(data) != "id\n1\n" {
+		t.Errorf("OpenArtifact() = %+v, %q", artifact, data)
+	}
+
+	if err := manager.DeleteTask("export-1"); err != nil {
+		t.Fatalf("DeleteTask() error = %v, want nil", err)
+	}
+
+	if _, err := store.Open("export-1", "customers.csv"); err == nil {
+		t.Error("Artifact still stored after task deletion")
+	}
+
+	if err := ArtifactsFromContext(context.Background()).Attach("a.txt", "", strings.NewReader("")); err == nil {
+		t.Error("Attach() outside task error = nil, want error")
+	}
+}
+
+func TestTaskManager_ArtifactsFromStaleAttempt(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	store := repository.NewMemoryArtifactStore()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       2,
+		Artifacts:     store,
+		LeaseDuration: 80 * time.Millisecond,
+		LeasePolicy:   LeasePolicyRequeue,
+	})
+
+	// первая попытка зависает, ее забирает reaper, вторая прикладывает артефакт
+	release := make(chan struct{})
+	staleErr := make(chan error, 1)
+	manager.RegisterExecutor("export", func(ctx context.Context, task *model.Task) (any, error) {
+		artifacts := ArtifactsFromContext(ctx)
+		if task.Attempt == 1 {
+			<-release
+			staleErr <- artifacts.Attach("report.txt", "", strings.NewReader("stale"))
+			return nil, nil
+		}
+		return nil, artifacts.Attach("report.txt", "", strings.NewReader("fresh"))
+	})
+
+	manager.CreateTask(TaskSpec{
+		ID:          "export-2",
+		Type:        "export",
+		Timeout:     20 * time.Millisecond,
+		RetryPolicy: &model.RetryPolicy{MaxAttempts: 2},
+	})
+	waitForStatus(t, manager, "export-2", model.StatusCompleted)
+	close(release)
+
+	if err := <-staleErr; err == nil || !strings.Contains(err.Error(), "no longer running") {
+		t.Errorf("Attach() from stale attempt error = %v, want no longer running", err)
+	}
+
+	_, content, err := manager.OpenArtifact("export-2", "report.txt")
+	if err != nil {
+		t.Fatalf("OpenArtifact() error = %v, want nil", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+
+	if string(data) != "fresh" {
+		t.Errorf("Artifact content = %q, want fresh", data)
+	}
+}
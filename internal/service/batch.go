@@ -0,0 +1,299 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type BatchSpec struct {
+	ID         string
+	Tasks      []TaskSpec
+	OnComplete *model.TaskTemplate
+}
+
+func (tm *TaskManager) CreateBatch(spec BatchSpec) (*model.Batch, error) {
+	if tm.isClosing() {
+		tm.logger.WithField("batch_id", spec.ID).Warn("Rejecting batch: task manager is shutting down")
+		return nil, ErrShuttingDown
+	}
+
+	tm.logger.WithField("batch_id", spec.ID).Info("Creating batch")
+
+	err := tm.validateBatch(&spec)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"batch_id": spec.ID,
+			"error":    err.Error(),
+		}).Warn("Invalid batch specification")
+		return nil, err
+	}
+
+	batch := &model.Batch{
+		ID:         spec.ID,
+		TaskIDs:    make([]string, len(spec.Tasks)),
+		Counts:     make(map[model.TaskStatus]int),
+		State:      model.BatchRunning,
+		OnComplete: spec.OnComplete,
+		CreatedAt:  time.Now(),
+	}
+
+	for i, taskSpec := range spec.Tasks {
+		batch.TaskIDs[i] = taskSpec.ID
+	}
+
+	tm.batchMutex.Lock()
+	err = tm.batches.Create(batch)
+	tm.batchMutex.Unlock()
+
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"batch_id": spec.ID,
+			"error":    err.Error(),
+		}).Error("Failed to create batch in repository")
+		return nil, fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	for i, taskSpec := range spec.Tasks {
+		_, err := tm.createTask(taskSpec)
+		if err != nil {
+			tm.abortBatch(batch, batch.TaskIDs[:i])
+			return nil, fmt.Errorf("failed to create batch: %w", err)
+		}
+	}
+
+	// задачи могли завершиться до того, как была создана последняя из них
+	tm.checkBatch(batch.ID)
+
+	tm.batchMutex.Lock()
+	defer tm.batchMutex.Unlock()
+
+	tm.logger.WithFields(logrus.Fields{
+		"batch_id": batch.ID,
+		"tasks":    len(batch.TaskIDs),
+	}).Info("Batch created successfully")
+	return copyBatch(batch), nil
+}
+
+func (tm *TaskManager) GetBatch(id string) (*model.Batch, error) {
+	batch, err := tm.batches.GetByID(id)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"batch_id": id,
+			"error":    err.Error(),
+		}).Warn("Batch not found")
+		return nil, err
+	}
+
+	tm.batchMutex.Lock()
+	defer tm.batchMutex.Unlock()
+
+	tm.refreshBatch(batch)
+	return copyBatch(batch), nil
+}
+
+// copyBatch возвращает копию, которую можно читать без batchMutex; вызывается под batchMutex
+func copyBatch(batch *model.Batch) *model.Batch {
+	copied := *batch
+	copied.TaskIDs = append([]string(nil), batch.TaskIDs...)
+	copied.DeletedTaskIDs = append([]string(nil), batch.DeletedTaskIDs...)
+	copied.Counts = make(map[model.TaskStatus]int, len(batch.Counts))
+	for status, count := range batch.Counts {
+		copied.Counts[status] = count
+	}
+	return &copied
+}
+
+// validateBatch назначает задачам без ID идентификаторы вида <batch>-<n>
+// и проверяет все задачи до создания первой из них
+func (tm *TaskManager) validateBatch(spec *BatchSpec) error {
+	if spec.ID == "" {
+		return validationErrorf("invalid batch: id is required")
+	}
+
+	if len(spec.Tasks) == 0 {
+		return validationErrorf("invalid batch: at least one task is required")
+	}
+
+	seen := make(map[string]bool, len(spec.Tasks))
+	for i := range spec.Tasks {
+		taskSpec := &spec.Tasks[i]
+		if taskSpec.ID == "" {
+			taskSpec.ID = batchTaskID(spec.ID, i+1)
+		}
+
+		if seen[taskSpec.ID] {
+			return validationErrorf("invalid batch: duplicate task ID %s", taskSpec.ID)
+		}
+		seen[taskSpec.ID] = true
+
+		if _, err := tm.repo.GetByID(taskSpec.ID); err == nil {
+			return fmt.Errorf("task with ID %s %w", taskSpec.ID, ErrConflict)
+		}
+
+		check := *taskSpec
+		if err := tm.validateSpec(&check); err != nil {
+			return validationErrorf("invalid batch: task %s: %w", taskSpec.ID, err)
+		}
+
+		taskSpec.BatchID = spec.ID
+	}
+
+	if spec.OnComplete != nil {
+		callbackSpec := taskSpecFromTemplate(*spec.OnComplete, "")
+		if err := tm.validateSpec(&callbackSpec); err != nil {
+			return validationErrorf("invalid batch: on_complete: %w", err)
+		}
+		spec.OnComplete.Type = callbackSpec.Type
+	}
+
+	return nil
+}
+
+// abortBatch удаляет группу и отменяет уже созданные задачи,
+// если одну из задач группы создать не удалось
+func (tm *TaskManager) abortBatch(batch *model.Batch, created []string) {
+	tm.batchMutex.Lock()
+	tm.batches.Delete(batch.ID)
+	tm.batchMutex.Unlock()
+
+	for _, taskID := range created {
+		tm.CancelTask(taskID)
+	}
+
+	tm.logger.WithFields(logrus.Fields{
+		"batch_id": batch.ID,
+		"created":  len(created),
+	}).Warn("Batch creation aborted")
+}
+
+func (tm *TaskManager) batchTaskFinished(task *model.Task) {
+	tm.checkBatch(task.BatchID)
+}
+
+// batchTaskDeleted отмечает задачу удаленной. Удаление фиксируется явно, а не по
+// отсутствию задачи, потому что задачи группы создаются не одновременно
+func (tm *TaskManager) batchTaskDeleted(task *model.Task) {
+	tm.batchMutex.Lock()
+	batch, err := tm.batches.GetByID(task.BatchID)
+	if err == nil {
+		batch.DeletedTaskIDs = append(batch.DeletedTaskIDs, task.ID)
+		tm.batches.Update(batch)
+	}
+	tm.batchMutex.Unlock()
+
+	tm.checkBatch(task.BatchID)
+}
+
+// checkBatch подводит итог группы, когда все ее задачи завершены,
+// и запускает задачу обратного вызова
+func (tm *TaskManager) checkBatch(id string) {
+	tm.batchMutex.Lock()
+	defer tm.batchMutex.Unlock()
+
+	batch, err := tm.batches.GetByID(id)
+	if err != nil || batch.IsCompleted() {
+		return
+	}
+
+	tm.refreshBatch(batch)
+	if batch.Progress < 100 {
+		tm.batches.Update(batch)
+		return
+	}
+
+	succeeded := batch.Counts[model.StatusCompleted]
+	switch {
+	case succeeded == len(batch.TaskIDs):
+		batch.State = model.BatchSucceeded
+	case succeeded == 0:
+		batch.State = model.BatchFailed
+	default:
+		batch.State = model.BatchPartialFailure
+	}
+
+	completedAt := time.Now()
+	batch.CompletedAt = &completedAt
+
+	if batch.OnComplete != nil {
+		tm.spawnBatchCallback(batch)
+	}
+
+	tm.batches.Update(batch)
+
+	tm.logger.WithFields(logrus.Fields{
+		"batch_id": batch.ID,
+		"state":    batch.State,
+	}).Info("Batch finished")
+}
+
+// refreshBatch пересчитывает счетчики по статусам задач; вызывается под batchMutex
+func (tm *TaskManager) refreshBatch(batch *model.Batch) {
+	counts := make(map[model.TaskStatus]int)
+	finished := len(batch.DeletedTaskIDs)
+
+	deleted := make(map[string]bool, len(batch.DeletedTaskIDs))
+	for _, taskID := range batch.DeletedTaskIDs {
+		deleted[taskID] = true
+	}
+
+	// статусы задач меняются воркерами под mutex
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	for _, taskID := range batch.TaskIDs {
+		if deleted[taskID] {
+			continue
+		}
+
+		task, err := tm.repo.GetByID(taskID)
+		if err != nil {
+			continue
+		}
+
+		counts[task.Status]++
+		if task.IsCompleted() {
+			finished++
+		}
+	}
+
+	batch.Counts = counts
+	batch.Progress = float64(finished) * 100 / float64(len(batch.TaskIDs))
+}
+
+// spawnBatchCallback создает задачу on_complete; без payload в шаблоне
+// задача получает итог группы
+func (tm *TaskManager) spawnBatchCallback(batch *model.Batch) {
+	spec := taskSpecFromTemplate(*batch.OnComplete, batchCallbackID(batch.ID))
+
+	if len(spec.Payload) == 0 {
+		spec.Payload, _ = json.Marshal(map[string]interface{}{
+			"batch_id": batch.ID,
+			"state":    batch.State,
+			"counts":   batch.Counts,
+		})
+	}
+
+	task, err := tm.createTask(spec)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"batch_id": batch.ID,
+			"task_id":  spec.ID,
+			"error":    err.Error(),
+		}).Error("Failed to create batch callback task")
+		return
+	}
+
+	batch.CallbackTaskID = task.ID
+}
+
+func batchTaskID(batchID string, n int) string {
+	return fmt.Sprintf("%s-%d", batchID, n)
+}
+
+func batchCallbackID(batchID string) string {
+	return fmt.Sprintf("%s.on_complete", batchID)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func waitForBatch(t *testing.T, manager *TaskManager, id string) *model.Batch {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		batch, err := manager.GetBatch(id)
+		if err == nil && batch.IsCompleted() {
+			return batch
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Batch %s did not finish", id)
+	return nil
+}
+
+func setupBatchManager(t *testing.T) *TaskManager {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 4)
+
+	manager.RegisterExecutor("ok", func(ctx context.Context, task *model.Task) (any, error) {
+		return "done", nil
+	})
+	manager.RegisterExecutor("fail", func(ctx context.Context, task *model.Task) (any, error) {
+		return nil, Permanent(fmt.Errorf("boom"))
+	})
+
+	return manager
+}
+
+func TestTaskManager_Batch_AllSucceeded(t *testing.T) {
+	manager := setupBatchManager(t)
+
+	_, err := manager.CreateBatch(BatchSpec{
+		ID:         "import",
+		Tasks:      []TaskSpec{{Type: "ok"}, {Type: "ok"}, {ID: "named", Type: "ok"}},
+		OnComplete: &model.TaskTemplate{Type: "ok"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatch() error = %v, want nil", err)
+	}
+
+	batch := waitForBatch(t, manager, "import")
+
+	if batch.State != model.BatchSucceeded {
+		t.Errorf("State = %v, want %v", batch.State, model.BatchSucceeded)
+	}
+
+	if batch.Counts[model.StatusCompleted] != 3 || batch.Progress != 100 {
+		t.Errorf("Counts = %v, progress = %v, want 3 completed and 100", batch.Counts, batch.Progress)
+	}
+
+	if batch.TaskIDs[0] != "import-1" || batch.TaskIDs[2] != "named" {
+		t.Errorf("TaskIDs = %v, want generated and explicit IDs", batch.TaskIDs)
+	}
+
+	callback := waitForStatus(t, manager, batch.CallbackTaskID, model.StatusCompleted)
+	if !strings.Contains(string(callback.Payload), `"state":"succeeded"`) {
+		t.Errorf("Callback payload = %s, want batch summary", callback.Payload)
+	}
+}
+
+func TestTaskManager_Batch_FinalState(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		want  model.BatchState
+	}{
+		{"partial failure", []string{"ok", "fail"}, model.BatchPartialFailure},
+		{"all failed", []string{"fail", "fail"}, model.BatchFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := setupBatchManager(t)
+
+			specs := make([]TaskSpec, len(tt.types))
+			for i, taskType := range tt.types {
+				specs[i] = TaskSpec{Type: taskType}
+			}
+
+			_, err := manager.CreateBatch(BatchSpec{ID: "batch", Tasks: specs})
+			if err != nil {
+				t.Fatalf("CreateBatch() error = %v, want nil", err)
+			}
+
+			batch := waitForBatch(t, manager, "batch")
+			if batch.State != tt.want {
+				t.Errorf("State = %v, want %v", batch.State, tt.want)
+			}
+
+			if batch.CallbackTaskID != "" {
+				t.Errorf("CallbackTaskID = %q, want empty without on_complete", batch.CallbackTaskID)
+			}
+		})
+	}
+}
+
+func TestTaskManager_CreateBatch_Invalid(t *testing.T) {
+	manager := setupBatchManager(t)
+
+	manager.CreateTask(TaskSpec{ID: "taken", Type: "ok"})
+
+	tests := []struct {
+		name  string
+		tasks []TaskSpec
+		want  string
+	}{
+		{"no tasks", nil, "invalid batch"},
+		{"unknown type", []TaskSpec{{Type: "missing"}}, "invalid batch"},
+		{"duplicate id", []TaskSpec{{ID: "a", Type: "ok"}, {ID: "a", Type: "ok"}}, "invalid batch"},
+		{"existing task", []TaskSpec{{ID: "taken", Type: "ok"}}, "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.CreateBatch(BatchSpec{ID: "invalid", Tasks: tt.tasks})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CreateBatch() error = %v, want %q", err, tt.want)
+			}
+
+			if _, err := manager.GetBatch("invalid"); err == nil {
+				t.Error("GetBatch() error = nil, want batch not stored")
+			}
+		})
+	}
+}
+
+func TestTaskManager_Batch_TaskDeleted(t *testing.T) {
+	manager := setupBatchManager(t)
+
+	_, err := manager.CreateBatch(BatchSpec{
+		ID: "partial",
+		Tasks: []TaskSpec{
+			{Type: "ok"},
+			{Type: "ok", RunAt: time.Now().Add(time.Hour)},
+		},
+		OnComplete: &model.TaskTemplate{Type: "ok"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatch() error = %v, want nil", err)
+	}
+
+	// первая задача должна завершиться до удаления второй
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if batch, _ := manager.GetBatch("partial"); batch.Counts[model.StatusCompleted] == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := manager.DeleteTask("partial-2"); err != nil {
+		t.Fatalf("DeleteTask() error = %v, want nil", err)
+	}
+
+	batch := waitForBatch(t, manager, "partial")
+
+	if batch.State != model.BatchPartialFailure || batch.Progress != 100 {
+		t.Errorf("State = %v, progress = %v, want %v and 100", batch.State, batch.Progress, model.BatchPartialFailure)
+	}
+
+	if len(batch.DeletedTaskIDs) != 1 || batch.DeletedTaskIDs[0] != "partial-2" {
+		t.Errorf("DeletedTaskIDs = %v, want [partial-2]", batch.DeletedTaskIDs)
+	}
+
+	if batch.CallbackTaskID == "" {
+		t.Error("CallbackTaskID is empty, want on_complete task")
+	}
+}
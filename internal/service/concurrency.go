@@ -0,0 +1,69 @@
+package service
+
+import "github.com/bambutcha/taskflow/internal/model"
+
+// concurrencyKey учитывает выполняющиеся задачи с общим ключом и задачи,
+// отложенные до освобождения слота. Отложенная задача не занимает воркер,
+// поэтому воркеры не блокируются, даже если все ключи заняты
+type concurrencyKey struct {
+	running int
+	waiting []*model.Task
+}
+
+// concurrencyKeyAvailable проверяет, есть ли свободный слот для ключа задачи,
+// и при его отсутствии откладывает задачу; вызывается под mutex
+func (tm *TaskManager) concurrencyKeyAvailable(task *model.Task) bool {
+	if task.ConcurrencyKey == "" {
+		return true
+	}
+
+	key, exists := tm.keys[task.ConcurrencyKey]
+	if !exists || key.running < task.ConcurrencyLimit {
+		return true
+	}
+
+	key.waiting = append(key.waiting, task)
+	return false
+}
+
+// acquireConcurrencyKey занимает слот ключа; вызывается под mutex
+func (tm *TaskManager) acquireConcurrencyKey(task *model.Task) {
+	if task.ConcurrencyKey == "" {
+		return
+	}
+
+	key, exists := tm.keys[task.ConcurrencyKey]
+	if !exists {
+		key = &concurrencyKey{}
+		tm.keys[task.ConcurrencyKey] = key
+	}
+	key.running++
+}
+
+// releaseConcurrencyKey освобождает слот и возвращает в очередь первую
+// из отложенных задач, которая еще ждет выполнения; вызывается под mutex
+func (tm *TaskManager) releaseConcurrencyKey(task *model.Task) {
+	if task.ConcurrencyKey == "" {
+		return
+	}
+
+	key, exists := tm.keys[task.ConcurrencyKey]
+	if !exists {
+		return
+	}
+	key.running--
+
+	for len(key.waiting) > 0 {
+		next := key.waiting[0]
+		key.waiting = key.waiting[1:]
+
+		if _, err := tm.repo.GetByID(next.ID); err == nil && next.IsPending() {
+			tm.enqueue(next)
+			break
+		}
+	}
+
+	if key.running == 0 && len(key.waiting) == 0 {
+		delete(tm.keys, task.ConcurrencyKey)
+	}
+}
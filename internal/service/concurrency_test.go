@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_ConcurrencyKey(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 3)
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	manager.RegisterExecutor("account", func(ctx context.Context, task *model.Task) (any, error) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(30 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return "synced", nil
+	})
+
+	ids := []string{"acc-1", "acc-2", "acc-3", "acc-4"}
+	for _, id := range ids {
+		manager.CreateTask(TaskSpec{ID: id, Type: "account", ConcurrencyKey: "customer-42"})
+	}
+
+	for _, id := range ids {
+		waitForStatus(t, manager, id, model.StatusCompleted)
+	}
+
+	if maxRunning != 1 {
+		t.Errorf("Max running tasks with one key = %d, want 1", maxRunning)
+	}
+}
+
+func TestTaskManager_ConcurrencyKey_Limit(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 4)
+
+	release := make(chan struct{})
+	manager.RegisterExecutor("hold", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "released", nil
+	})
+
+	ids := []string{"hold-1", "hold-2", "hold-3"}
+	for _, id := range ids {
+		manager.CreateTask(TaskSpec{ID: id, Type: "hold", ConcurrencyKey: "shared", ConcurrencyLimit: 2})
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	running := 0
+	for _, id := range ids {
+		if task, _ := manager.GetTask(id); task.Status == model.StatusRunning {
+			running++
+		}
+	}
+
+	if running != 2 {
+		t.Errorf("Running tasks = %d, want 2", running)
+	}
+
+	close(release)
+
+	for _, id := range ids {
+		waitForStatus(t, manager, id, model.StatusCompleted)
+	}
+}
+
+func TestTaskManager_ConcurrencyKey_NoDeadlock(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 2)
+
+	release := make(chan struct{})
+	manager.RegisterExecutor("hold", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "released", nil
+	})
+
+	// оба воркера заняты задачами с ключами, остальные задачи с этими
+	// ключами не должны занимать воркеры и задерживать задачи без ключа
+	manager.CreateTask(TaskSpec{ID: "a-1", Type: "hold", ConcurrencyKey: "a"})
+	manager.CreateTask(TaskSpec{ID: "b-1", Type: "hold", ConcurrencyKey: "b"})
+	waitForStatus(t, manager, "a-1", model.StatusRunning)
+	waitForStatus(t, manager, "b-1", model.StatusRunning)
+
+	manager.CreateTask(TaskSpec{ID: "a-2", Type: "hold", ConcurrencyKey: "a"})
+	manager.CreateTask(TaskSpec{ID: "b-2", Type: "hold", ConcurrencyKey: "b"})
+	manager.CreateTask(TaskSpec{ID: "free", Type: "hold"})
+
+	close(release)
+
+	for _, id := range []string{"a-1", "b-1", "a-2", "b-2", "free"} {
+		waitForStatus(t, manager, id, model.StatusCompleted)
+	}
+}
+
+func TestTaskManager_ConcurrencyKey_CancelledWaiter(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 2)
+
+	release := make(chan struct{})
+	manager.RegisterExecutor("hold", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "released", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "first", Type: "hold", ConcurrencyKey: "key"})
+	waitForStatus(t, manager, "first", model.StatusRunning)
+
+	manager.CreateTask(TaskSpec{ID: "cancelled", Type: "hold", ConcurrencyKey: "key"})
+	manager.CreateTask(TaskSpec{ID: "last", Type: "hold", ConcurrencyKey: "key"})
+	time.Sleep(20 * time.Millisecond)
+
+	manager.CancelTask("cancelled")
+	close(release)
+
+	waitForStatus(t, manager, "first", model.StatusCompleted)
+	waitForStatus(t, manager, "last", model.StatusCompleted)
+}
+
+func TestTaskManager_ConcurrencyLimit_Invalid(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 0)
+
+	if _, err := manager.CreateTask(TaskSpec{ID: "no-key", ConcurrencyLimit: 2}); err == nil {
+		t.Error("CreateTask() error = nil for limit without key, want error")
+	}
+
+	if _, err := manager.CreateTask(TaskSpec{ID: "negative", ConcurrencyKey: "k", ConcurrencyLimit: -1}); err == nil {
+		t.Error("CreateTask() error = nil for negative limit, want error")
+	}
+
+	task, err := manager.CreateTask(TaskSpec{ID: "default-limit", ConcurrencyKey: "k"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if task.ConcurrencyLimit != 1 {
+		t.Errorf("ConcurrencyLimit = %d, want 1", task.ConcurrencyLimit)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronExpression - разобранное cron выражение из пяти полей:
+// минуты, часы, день месяца, месяц, день недели
+type cronExpression struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{min: 0, max: 59}
+	hourField   = cronField{min: 0, max: 23}
+	domField    = cronField{min: 1, max: 31}
+	monthField  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseCron(expr string) (*cronExpression, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, exists := cronMacros[strings.ToLower(spec)]; exists {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, validationErrorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	var c cronExpression
+	var err error
+
+	if c.minute, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, validationErrorf("invalid cron expression %q: minute: %w", expr, err)
+	}
+	if c.hour, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, validationErrorf("invalid cron expression %q: hour: %w", expr, err)
+	}
+	if c.dom, err = parseCronField(fields[2], domField); err != nil {
+		return nil, validationErrorf("invalid cron expression %q: day of month: %w", expr, err)
+	}
+	if c.month, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, validationErrorf("invalid cron expression %q: month: %w", expr, err)
+	}
+	if c.dow, err = parseCronField(fields[4], dowField); err != nil {
+		return nil, validationErrorf("invalid cron expression %q: day of week: %w", expr, err)
+	}
+
+	// 7 и 0 оба обозначают воскресенье
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	// как в vixie cron, поле, начинающееся с *, не ограничивает день, даже с шагом (*/2)
+	c.domStar = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	c.dowStar = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return &c, nil
+}
+
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart = part[:i]
+		}
+
+		var start, end int
+		var err error
+
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			start, end = bounds.min, bounds.max
+		case strings.Contains(rangePart, "-"):
+			limits := strings.SplitN(rangePart, "-", 2)
+			if start, err = parseCronValue(limits[0], bounds); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(limits[1], bounds); err != nil {
+				return 0, err
+			}
+		default:
+			if start, err = parseCronValue(rangePart, bounds); err != nil {
+				return 0, err
+			}
+			end = start
+			// "5/15" означает "каждые 15, начиная с 5"
+			if step > 1 {
+				end = bounds.max
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, fmt.Errorf("value out of range in %q (allowed %d-%d)", part, bounds.min, bounds.max)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(value string, bounds cronField) (int, error) {
+	if number, err := strconv.Atoi(value); err == nil {
+		return number, nil
+	}
+
+	if number, exists := bounds.names[strings.ToLower(value)]; exists {
+		return number, nil
+	}
+
+	return 0, fmt.Errorf("invalid value %q", value)
+}
+
+// next возвращает первый момент строго после after, подходящий под выражение.
+// Поиск ведется в часовом поясе after; нулевое время означает, что момент не найден
+func (c *cronExpression) next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for c.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !c.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for c.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for c.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// dayMatches следует семантике cron: если ограничены и день месяца,
+// и день недели, достаточно совпадения любого из них
+func (c *cronExpression) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) error = nil, want error", expr)
+		}
+	}
+}
+
+func TestCronExpression_Next(t *testing.T) {
+	base := time.Date(2025, 7, 2, 19, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 7, 2, 19, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 7, 2, 19, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, 7, 3, 2, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, 7, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 8 29 2 *", time.Date(2028, 2, 29, 8, 30, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2025, 7, 6, 12, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 7, 2, 20, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 */2 * 1", time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		cron, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q) error = %v", tt.expr, err)
+		}
+
+		if got := cron.next(base); !got.Equal(tt.want) {
+			t.Errorf("next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCronExpression_NextInTimezone(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	cron, _ := parseCron("0 2 * * *")
+	base := time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	got := cron.next(base.In(location))
+	want := time.Date(2025, 7, 2, 23, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("next() = %v, want %v", got.UTC(), want)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// lookupDependencies проверяет, что все родительские задачи существуют
+// и новая задача не замыкает цикл; вызывается под mutex
+func (tm *TaskManager) lookupDependencies(id string, dependsOn []string) ([]*model.Task, error) {
+	parents := make([]*model.Task, 0, len(dependsOn))
+	seen := make(map[string]bool, len(dependsOn))
+
+	for _, parentID := range dependsOn {
+		if parentID == id {
+			return nil, validationErrorf("dependency cycle: task %s depends on itself", id)
+		}
+		if seen[parentID] {
+			continue
+		}
+		seen[parentID] = true
+
+		parent, err := tm.repo.GetByID(parentID)
+		if err != nil {
+			return nil, validationErrorf("unknown dependency: %s", parentID)
+		}
+
+		if tm.reachesTask(parent, id, make(map[string]bool)) {
+			return nil, validationErrorf("dependency cycle: %s already depends on %s", parentID, id)
+		}
+
+		parents = append(parents, parent)
+	}
+
+	return parents, nil
+}
+
+// reachesTask ищет target среди предков задачи. ID удаленной задачи
+// может быть использован повторно, поэтому цикл возможен и для новой задачи
+func (tm *TaskManager) reachesTask(task *model.Task, target string, visited map[string]bool) bool {
+	if visited[task.ID] {
+		return false
+	}
+	visited[task.ID] = true
+
+	for _, parentID := range task.DependsOn {
+		if parentID == target {
+			return true
+		}
+
+		parent, err := tm.repo.GetByID(parentID)
+		if err == nil && tm.reachesTask(parent, target, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependencyState возвращает готовность задачи к запуску и,
+// если какой-то родитель завершился неуспешно, причину отказа
+func (tm *TaskManager) dependencyState(task *model.Task) (bool, error) {
+	ready := true
+
+	for _, parentID := range task.DependsOn {
+		parent, err := tm.repo.GetByID(parentID)
+		if err != nil {
+			return false, fmt.Errorf("dependency %s was deleted", parentID)
+		}
+
+		switch parent.Status {
+		case model.StatusCompleted:
+		case model.StatusFailed:
+			return false, fmt.Errorf("dependency %s failed", parentID)
+		case model.StatusCancelled:
+			return false, fmt.Errorf("dependency %s was cancelled", parentID)
+		default:
+			ready = false
+		}
+	}
+
+	return ready, nil
+}
+
+// resolveDependents пересматривает заблокированные задачи, зависящие от parent,
+// после его завершения или удаления
+func (tm *TaskManager) resolveDependents(parent *model.Task) {
+	var released, failed []*model.Task
+
+	tm.mutex.Lock()
+	for _, childID := range parent.Dependents {
+		child, err := tm.repo.GetByID(childID)
+		if err != nil || child.Status != model.StatusBlocked {
+			continue
+		}
+
+		logger := tm.logger.WithFields(logrus.Fields{
+			"task_id":   child.ID,
+			"parent_id": parent.ID,
+		})
+
+		ready, depErr := tm.dependencyState(child)
+		if depErr != nil {
+			child.ErrorCode = model.ErrorCodeDependencyFailed
+			tm.failTask(child, depErr, logger)
+			failed = append(failed, child)
+			continue
+		}
+
+		if !ready {
+			continue
+		}
+
+		tm.unblock(child)
+		err = tm.repo.Update(child)
+		if err != nil {
+			logger.WithField("error", err.Error()).Error("Failed to update unblocked task")
+			continue
+		}
+
+		tm.logEvent(child, model.LogLevelInfo, "dependencies completed, task unblocked")
+		logger.Info("All dependencies completed, task unblocked")
+		released = append(released, child)
+	}
+	tm.mutex.Unlock()
+
+	for _, child := range released {
+		tm.dispatch(child)
+	}
+
+	for _, child := range failed {
+		tm.taskFinished(child)
+	}
+}
+
+// unblock переводит задачу с выполненными зависимостями в очередь
+// или к планировщику, если ее время еще не наступило; вызывается под mutex
+func (tm *TaskManager) unblock(task *model.Task) {
+	if task.RunAt != nil && task.RunAt.After(time.Now()) {
+		task.Status = model.StatusScheduled
+	} else {
+		task.Status = model.StatusPending
+	}
+}
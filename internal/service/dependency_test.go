@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func setupDependencyManager(t *testing.T) (*TaskManager, chan struct{}) {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 2)
+
+	release := make(chan struct{})
+	manager.RegisterExecutor("gated", func(ctx context.Context, task *model.Task) (any, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return "done", nil
+	})
+	manager.RegisterExecutor("broken", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return nil, errors.New("upstream unavailable")
+	})
+
+	return manager, release
+}
+
+func TestTaskManager_DependencyBlocksUntilParentsComplete(t *testing.T) {
+	manager, release := setupDependencyManager(t)
+
+	manager.CreateTask(TaskSpec{ID: "extract", Type: "gated"})
+	manager.CreateTask(TaskSpec{ID: "fetch", Type: "gated"})
+
+	child, err := manager.CreateTask(TaskSpec{ID: "load", DependsOn: []string{"extract", "fetch"}})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if child.Status != model.StatusBlocked {
+		t.Errorf("CreateTask() Status = %v, want %v", child.Status, model.StatusBlocked)
+	}
+
+	parent, _ := manager.GetTask("extract")
+	if len(parent.Dependents) != 1 || parent.Dependents[0] != "load" {
+		t.Errorf("Parent dependents = %v, want [load]", parent.Dependents)
+	}
+
+	close(release)
+
+	waitForStatus(t, manager, "load", model.StatusCompleted)
+}
+
+func TestTaskManager_DependencyOnCompletedParent(t *testing.T) {
+	manager, release := setupDependencyManager(t)
+	close(release)
+
+	manager.CreateTask(TaskSpec{ID: "extract", Type: "gated"})
+	waitForStatus(t, manager, "extract", model.StatusCompleted)
+
+	child, err := manager.CreateTask(TaskSpec{ID: "load", DependsOn: []string{"extract"}})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if child.Status == model.StatusBlocked {
+		t.Error("Task with completed parents is blocked")
+	}
+
+	waitForStatus(t, manager, "load", model.StatusCompleted)
+}
+
+func TestTaskManager_DependencyFailureCascades(t *testing.T) {
+	manager, release := setupDependencyManager(t)
+
+	manager.CreateTask(TaskSpec{ID: "extract", Type: "broken"})
+	manager.CreateTask(TaskSpec{ID: "transform", DependsOn: []string{"extract"}})
+	manager.CreateTask(TaskSpec{ID: "load", DependsOn: []string{"transform"}})
+
+	close(release)
+
+	transform := waitForStatus(t, manager, "transform", model.StatusFailed)
+	if transform.ErrorCode != model.ErrorCodeDependencyFailed {
+		t.Errorf("Error code = %q, want %q", transform.ErrorCode, model.ErrorCodeDependencyFailed)
+	}
+
+	if !strings.Contains(transform.Error, "extract failed") {
+		t.Errorf("Error = %q, want reason naming the failed parent", transform.Error)
+	}
+
+	load := waitForStatus(t, manager, "load", model.StatusFailed)
+	if !strings.Contains(load.Error, "transform failed") {
+		t.Errorf("Error = %q, want reason naming the failed parent", load.Error)
+	}
+}
+
+func TestTaskManager_DependencyCancelledParent(t *testing.T) {
+	manager, release := setupDependencyManager(t)
+	defer close(release)
+
+	manager.CreateTask(TaskSpec{ID: "extract", Type: "gated"})
+	manager.CreateTask(TaskSpec{ID: "load", DependsOn: []string{"extract"}})
+
+	manager.CancelTask("extract")
+
+	load := waitForStatus(t, manager, "load", model.StatusFailed)
+	if !strings.Contains(load.Error, "extract was cancelled") {
+		t.Errorf("Error = %q, want cancellation reason", load.Error)
+	}
+}
+
+func TestTaskManager_DependencyValidation(t *testing.T) {
+	manager, release := setupDependencyManager(t)
+	defer close(release)
+
+	_, err := manager.CreateTask(TaskSpec{ID: "orphan", DependsOn: []string{"missing"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown dependency") {
+		t.Errorf("CreateTask() error = %v, want unknown dependency", err)
+	}
+
+	_, err = manager.CreateTask(TaskSpec{ID: "self", DependsOn: []string{"self"}})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("CreateTask() error = %v, want dependency cycle", err)
+	}
+
+	// ID удаленной задачи переиспользуется так, что граф замыкается
+	manager.CreateTask(TaskSpec{ID: "a", Type: "gated"})
+	manager.CreateTask(TaskSpec{ID: "b", DependsOn: []string{"a"}})
+	manager.CancelTask("a")
+	manager.DeleteTask("a")
+
+	_, err = manager.CreateTask(TaskSpec{ID: "a", DependsOn: []string{"b"}})
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("CreateTask() error = %v, want dependency cycle", err)
+	}
+}
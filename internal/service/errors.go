@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+// Категории ошибок сервиса; обработчики выбирают по ним HTTP статус через errors.Is
+var (
+	ErrNotFound            = repository.ErrNotFound
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrConflict            = repository.ErrAlreadyExists
+	ErrValidation          = errors.New("validation failed")
+	ErrIdempotencyMismatch = errors.New("idempotency key was used with a different request")
+	ErrShuttingDown        = errors.New("task manager is shutting down")
+)
+
+// kindError относит ошибку к категории kind, не меняя ее текст
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+func kindErrorf(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+func validationErrorf(format string, args ...any) error {
+	return kindErrorf(ErrValidation, format, args...)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_ErrorKinds(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.CreateTask(TaskSpec{ID: "done"})
+	waitForStatus(t, manager, "done", model.StatusCompleted)
+
+	_, duplicateErr := manager.CreateTask(TaskSpec{ID: "done"})
+	_, unknownTypeErr := manager.CreateTask(TaskSpec{ID: "other", Type: "missing"})
+	_, cycleErr := manager.CreateTask(TaskSpec{ID: "self", DependsOn: []string{"self"}})
+	_, _, _ = manager.CreateTaskIdempotent("key-1", "a", TaskSpec{ID: "idem"})
+	_, _, mismatchErr := manager.CreateTaskIdempotent("key-1", "b", TaskSpec{ID: "idem"})
+	_, cancelErr := manager.CancelTask("done")
+	_, getErr := manager.GetTask("missing")
+	deleteErr := manager.DeleteTask("missing")
+	_, scheduleErr := manager.CreateSchedule(ScheduleSpec{ID: "nightly", Cron: "0 0 * *"})
+	_, workflowErr := manager.CreateWorkflow(WorkflowSpec{ID: "empty"})
+	_, _, artifactErr := manager.OpenArtifact("done", "report.txt")
+
+	manager.Shutdown(context.Background())
+	_, shutdownErr := manager.CreateTask(TaskSpec{ID: "late"})
+
+	tests := []struct {
+		name    string
+		err     error
+		kind    error
+		message string
+	}{
+		{"duplicate task", duplicateErr, ErrConflict, "failed to create task: task with ID done already exists"},
+		{"unknown type", unknownTypeErr, ErrValidation, "unknown task type: missing"},
+		{"dependency cycle", cycleErr, ErrValidation, "dependency cycle: task self depends on itself"},
+		{"idempotency mismatch", mismatchErr, ErrIdempotencyMismatch, "idempotency key key-1 was used with a different request"},
+		{"cancel finished", cancelErr, ErrConflict, "cannot cancel finished task"},
+		{"get missing", getErr, ErrNotFound, "task with ID missing not found"},
+		{"delete missing", deleteErr, ErrTaskNotFound, "task not found: task with ID missing not found"},
+		{"invalid cron", scheduleErr, ErrValidation, `invalid cron expression "0 0 * *": expected 5 fields, got 4`},
+		{"invalid workflow", workflowErr, ErrValidation, "invalid workflow: at least one step is required"},
+		{"missing artifact", artifactErr, ErrNotFound, "artifact report.txt not found"},
+		{"shutting down", shutdownErr, ErrShuttingDown, "task manager is shutting down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.kind) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", tt.err, tt.kind)
+			}
+			if tt.err != nil && tt.err.Error() != tt.message {
+				t.Errorf("Error() = %q, want %q", tt.err.Error(), tt.message)
+			}
+		})
+	}
+
+	if errors.Is(deleteErr, ErrValidation) || errors.Is(unknownTypeErr, ErrNotFound) {
+		t.Error("Errors match a category they do not belong to")
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// DefaultTaskType используется, если при создании задачи тип не указан
+const DefaultTaskType = "default"
+
+// Executor выполняет задачу своего типа и возвращает результат - любое значение,
+// которое кодируется в JSON; json.RawMessage сохраняется в задаче как есть
+type Executor func(ctx context.Context, task *model.Task) (any, error)
+
+type executorRegistry struct {
+	executors map[string]Executor
+	mutex     sync.RWMutex
+}
+
+func newExecutorRegistry() *executorRegistry {
+	return &executorRegistry{
+		executors: make(map[string]Executor),
+	}
+}
+
+func (r *executorRegistry) register(taskType string, executor Executor) error {
+	if taskType == "" {
+		return fmt.Errorf("task type cannot be empty")
+	}
+	if executor == nil {
+		return fmt.Errorf("executor cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.executors[taskType] = executor
+	return nil
+}
+
+func (r *executorRegistry) get(taskType string) (Executor, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	executor, exists := r.executors[taskType]
+	return executor, exists
+}
+
+type workerIDKey struct{}
+
+// WorkerIDFromContext возвращает номер воркера, выполняющего задачу
+func WorkerIDFromContext(ctx context.Context) int {
+	workerID, _ := ctx.Value(workerIDKey{}).(int)
+	return workerID
+}
+
+func (tm *TaskManager) RegisterExecutor(taskType string, executor Executor) error {
+	err := tm.executors.register(taskType, executor)
+	if err != nil {
+		return err
+	}
+
+	tm.logger.WithField("task_type", taskType).Info("Executor registered")
+	return nil
+}
+
+func (tm *TaskManager) HasExecutor(taskType string) bool {
+	_, exists := tm.executors.get(taskType)
+	return exists
+}
+
+// sleepExecutor имитирует долгую IO-bound операцию
+func (tm *TaskManager) sleepExecutor(ctx context.Context, task *model.Task) (any, error) {
+	var duration time.Duration
+	if tm.testMode {
+		duration = 100 * time.Millisecond
+	} else {
+		duration = time.Duration(3+rand.Intn(3)) * time.Minute
+	}
+
+	TaskLoggerFromContext(ctx).Infof("simulating IO-bound work for %s", duration)
+
+	select {
+	case <-time.After(duration):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return fmt.Sprintf("Task completed by worker %d", WorkerIDFromContext(ctx)), nil
+}
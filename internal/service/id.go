@@ -0,0 +1,85 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var idPrefixPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{0,31}$`)
+
+// idGenerator выдает ULID: 48 бит времени в миллисекундах и 80 случайных бит.
+// Внутри одной миллисекунды случайная часть увеличивается на единицу,
+// поэтому ID, выданные одним генератором, упорядочены по времени создания
+type idGenerator struct {
+	lastMs   uint64
+	lastHigh uint16
+	lastLow  uint64
+	mutex    sync.Mutex
+}
+
+func (g *idGenerator) next(now time.Time) string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	ms := uint64(now.UnixMilli())
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		g.lastLow++
+		if g.lastLow == 0 {
+			g.lastHigh++
+			if g.lastHigh == 0 {
+				ms++
+			}
+		}
+	} else {
+		var entropy [10]byte
+		rand.Read(entropy[:])
+		g.lastHigh = binary.BigEndian.Uint16(entropy[:2])
+		g.lastLow = binary.BigEndian.Uint64(entropy[2:])
+	}
+	g.lastMs = ms
+
+	hi := ms<<16 | uint64(g.lastHigh)
+	return encodeULID(hi, g.lastLow)
+}
+
+// encodeULID записывает 128-битное значение hi:lo 26 символами Crockford base32
+func encodeULID(hi, lo uint64) string {
+	var out [26]byte
+	for i := range out {
+		shift := uint(125 - 5*i)
+		var chunk uint64
+		switch {
+		case shift >= 64:
+			chunk = hi >> (shift - 64)
+		case shift == 0:
+			chunk = lo
+		default:
+			chunk = lo>>shift | hi<<(64-shift)
+		}
+		out[i] = crockfordAlphabet[chunk&31]
+	}
+	return string(out[:])
+}
+
+// generateTaskID возвращает ULID с необязательным префиксом вида "report_"
+func (tm *TaskManager) generateTaskID(prefix string) string {
+	id := tm.ids.next(time.Now())
+	if prefix == "" {
+		return id
+	}
+	return fmt.Sprintf("%s_%s", prefix, id)
+}
+
+func validateIDPrefix(prefix string) error {
+	if prefix != "" && !idPrefixPattern.MatchString(prefix) {
+		return validationErrorf("invalid id prefix: must start with a letter and contain up to 32 letters, digits or dashes")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestIDGenerator_Sortable(t *testing.T) {
+	var generator idGenerator
+	now := time.Date(2025, 7, 3, 12, 0, 0, 0, time.UTC)
+
+	previous := ""
+	for i := 0; i < 1000; i++ {
+		// половина ID выдается в одной миллисекунде
+		id := generator.next(now.Add(time.Duration(i/2) * time.Millisecond))
+		if len(id) != 26 {
+			t.Fatalf("next() = %q, want 26 characters", id)
+		}
+		if id <= previous {
+			t.Fatalf("next() = %q, want greater than %q", id, previous)
+		}
+		previous = id
+	}
+
+	// время в прошлом не нарушает порядок
+	if id := generator.next(now); id <= previous {
+		t.Errorf("next() = %q after clock step back, want greater than %q", id, previous)
+	}
+}
+
+func TestEncodeULID(t *testing.T) {
+	if got := encodeULID(0, 0); got != strings.Repeat("0", 26) {
+		t.Errorf("encodeULID(0, 0) = %q", got)
+	}
+
+	if got := encodeULID(^uint64(0), ^uint64(0)); got != "7"+strings.Repeat("Z", 25) {
+		t.Errorf("encodeULID(max) = %q", got)
+	}
+}
+
+func TestTaskManager_CreateTaskGeneratedID(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 0)
+
+	task, err := manager.CreateTask(TaskSpec{IDPrefix: "email"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if !strings.HasPrefix(task.ID, "email_") {
+		t.Errorf("task.ID = %q, want prefix email_", task.ID)
+	}
+
+	plain, err := manager.CreateTask(TaskSpec{})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if len(plain.ID) != 26 {
+		t.Errorf("task.ID = %q, want bare ULID", plain.ID)
+	}
+
+	_, err = manager.CreateTask(TaskSpec{IDPrefix: "1-starts-with-digit"})
+	if err == nil || !strings.Contains(err.Error(), "invalid id prefix") {
+		t.Errorf("CreateTask() error = %v, want invalid id prefix", err)
+	}
+}
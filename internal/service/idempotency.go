@@ -0,0 +1,101 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultIdempotencyTTL используется, если окно хранения ключей не задано
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// CreateTaskIdempotent создает задачу не более одного раза на ключ. Повтор
+// с тем же отпечатком запроса возвращает снимок задачи на момент создания
+// и replayed = true, повтор с другим отпечатком - ошибку
+func (tm *TaskManager) CreateTaskIdempotent(key, fingerprint string, spec TaskSpec) (task *model.Task, replayed bool, err error) {
+	tm.idempotencyMutex.Lock()
+	defer tm.idempotencyMutex.Unlock()
+
+	logger := tm.logger.WithFields(logrus.Fields{
+		"task_id":         spec.ID,
+		"idempotency_key": key,
+	})
+
+	now := time.Now()
+	record, err := tm.idempotency.Get(key, now)
+	if err == nil {
+		if record.Fingerprint != fingerprint {
+			logger.Warn("Idempotency key reused with a different request")
+			return nil, false, kindErrorf(ErrIdempotencyMismatch, "idempotency key %s was used with a different request", key)
+		}
+
+		var original model.Task
+		if err := json.Unmarshal(record.Response, &original); err != nil {
+			return nil, false, fmt.Errorf("failed to restore idempotent response: %w", err)
+		}
+
+		logger.Info("Returning task created earlier with the same idempotency key")
+		return &original, true, nil
+	}
+
+	task, err = tm.CreateTask(spec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tm.mutex.Lock()
+	response, err := json.Marshal(task)
+	tm.mutex.Unlock()
+
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to snapshot task for idempotency key")
+		return task, false, nil
+	}
+
+	err = tm.idempotency.Save(&model.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		TaskID:      task.ID,
+		Response:    response,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(tm.idempotencyTTL),
+	})
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to save idempotency key")
+	}
+
+	return task, false, nil
+}
+
+// purgeIdempotencyKeys периодически удаляет ключи, у которых истекло окно хранения
+func (tm *TaskManager) purgeIdempotencyKeys() {
+	interval := time.Minute
+	if tm.idempotencyTTL < interval {
+		interval = tm.idempotencyTTL
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var now time.Time
+		select {
+		case now = <-ticker.C:
+		case <-tm.done:
+			return
+		}
+
+		deleted, err := tm.idempotency.DeleteExpired(now)
+		if err != nil {
+			tm.logger.WithField("error", err.Error()).Error("Failed to purge idempotency keys")
+			continue
+		}
+
+		if deleted > 0 {
+			tm.logger.WithField("count", deleted).Debug("Expired idempotency keys purged")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_CreateTaskIdempotent(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 0)
+
+	task, replayed, err := manager.CreateTaskIdempotent("key-1", "body-a", TaskSpec{ID: "idempotent-task"})
+	if err != nil {
+		t.Fatalf("CreateTaskIdempotent() error = %v, want nil", err)
+	}
+
+	if replayed {
+		t.Error("CreateTaskIdempotent() replayed = true on first call, want false")
+	}
+
+	again, replayed, err := manager.CreateTaskIdempotent("key-1", "body-a", TaskSpec{ID: "idempotent-task"})
+	if err != nil {
+		t.Fatalf("Repeated CreateTaskIdempotent() error = %v, want nil", err)
+	}
+
+	if !replayed || again.ID != task.ID || !again.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("Repeated call = %+v, replayed %v, want original task", again, replayed)
+	}
+
+	_, _, err = manager.CreateTaskIdempotent("key-1", "body-b", TaskSpec{ID: "other-task"})
+	if err == nil || !strings.Contains(err.Error(), "different request") {
+		t.Errorf("CreateTaskIdempotent() error = %v, want different request error", err)
+	}
+
+	if _, err := manager.GetTask("other-task"); err == nil {
+		t.Error("Task created for reused key with a different request")
+	}
+}
+
+func TestTaskManager_CreateTaskIdempotent_Expired(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{IdempotencyTTL: 20 * time.Millisecond})
+
+	manager.CreateTaskIdempotent("key-1", "body-a", TaskSpec{ID: "first"})
+	time.Sleep(30 * time.Millisecond)
+
+	task, replayed, err := manager.CreateTaskIdempotent("key-1", "body-b", TaskSpec{ID: "second"})
+	if err != nil {
+		t.Fatalf("CreateTaskIdempotent() error = %v after window, want nil", err)
+	}
+
+	if replayed || task.ID != "second" || task.Status != model.StatusPending {
+		t.Errorf("CreateTaskIdempotent() = %s, replayed %v, want new task", task.ID, replayed)
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultLeaseDuration - сколько выполняющаяся задача живет без heartbeat
+const DefaultLeaseDuration = time.Minute
+
+// LeasePolicy определяет, что происходит с задачей, аренда которой истекла
+type LeasePolicy string
+
+const (
+	// LeasePolicyFail переводит задачу в failed с error_code lease_expired
+	LeasePolicyFail LeasePolicy = "fail"
+	// LeasePolicyRequeue возвращает задачу в очередь как новую попытку
+	LeasePolicyRequeue LeasePolicy = "requeue"
+)
+
+// Lease передается исполнителю через контекст. Аренду продлевают Heartbeat
+// и отчеты о прогрессе исполнителя; у попытки с таймаутом ее до дедлайна
+// продлевает и воркер. Исполнителя, не вернувшегося за срок аренды, забирает reaper
+type Lease struct {
+	tm      *TaskManager
+	task    *model.Task
+	attempt int
+}
+
+type leaseKey struct{}
+
+// LeaseFromContext возвращает аренду выполняемой задачи или nil вне TaskManager
+func LeaseFromContext(ctx context.Context) *Lease {
+	lease, _ := ctx.Value(leaseKey{}).(*Lease)
+	return lease
+}
+
+// Heartbeat продлевает аренду. Ошибка означает, что аренда уже потеряна
+// и исполнителю стоит прекратить работу
+func (l *Lease) Heartbeat() error {
+	if l == nil {
+		return nil
+	}
+
+	l.tm.mutex.Lock()
+	defer l.tm.mutex.Unlock()
+
+	task := l.task
+	if task.Attempt != l.attempt || !task.IsRunning() || task.LeaseExpiresAt == nil {
+		return fmt.Errorf("lease lost: attempt %d of task %s is no longer running", l.attempt, task.ID)
+	}
+
+	// частые heartbeat не пишутся в хранилище чаще десятой доли срока аренды
+	now := time.Now()
+	if task.LastHeartbeatAt != nil && now.Sub(*task.LastHeartbeatAt) < l.tm.leaseDuration/10 {
+		return nil
+	}
+
+	tm := l.tm
+	tm.renewLease(task, now)
+	err := tm.repo.Update(task)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": task.ID,
+			"error":   err.Error(),
+		}).Warn("Failed to save task heartbeat")
+	}
+	return nil
+}
+
+// keepLease продлевает аренду, пока не вызван stop или не наступил дедлайн попытки.
+// Попытку без таймаута воркер не продлевает: иначе зависший исполнитель держал бы
+// аренду вечно, и о том, что он жив, сообщают только его Heartbeat и Report
+func (tm *TaskManager) keepLease(ctx context.Context, lease *Lease) (stop func()) {
+	if _, ok := ctx.Deadline(); !ok {
+		return func() {}
+	}
+
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(tm.leaseDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if lease.Heartbeat() != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stopped)
+		<-done
+	}
+}
+
+// renewLease продлевает аренду задачи от момента now; вызывается под mutex
+func (tm *TaskManager) renewLease(task *model.Task, now time.Time) {
+	expiresAt := now.Add(tm.leaseDuration)
+	task.LastHeartbeatAt = &now
+	task.LeaseExpiresAt = &expiresAt
+}
+
+// reapLeases периодически ищет выполняющиеся задачи с истекшей арендой
+func (tm *TaskManager) reapLeases() {
+	ticker := time.NewTicker(tm.leaseDuration / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			tm.expireLeases(now)
+		case <-tm.done:
+			return
+		}
+	}
+}
+
+func (tm *TaskManager) expireLeases(now time.Time) {
+	var requeued, failed []*model.Task
+
+	tm.mutex.Lock()
+	for id := range tm.cancels {
+		task, err := tm.repo.GetByID(id)
+		if err != nil || !task.IsRunning() || task.LeaseExpiresAt == nil || task.LeaseExpiresAt.After(now) {
+			continue
+		}
+
+		if tm.expireLease(task, now) {
+			failed = append(failed, task)
+		} else {
+			requeued = append(requeued, task)
+		}
+	}
+	tm.mutex.Unlock()
+
+	for _, task := range requeued {
+		tm.enqueue(task)
+	}
+
+	for _, task := range failed {
+		tm.taskFinished(task)
+	}
+}
+
+// expireLease отбирает задачу у зависшего исполнителя и возвращает true,
+// если задача перешла в конечный статус; вызывается под mutex
+func (tm *TaskManager) expireLease(task *model.Task, now time.Time) bool {
+	logger := tm.logger.WithFields(logrus.Fields{
+		"task_id":           task.ID,
+		"attempt":           task.Attempt,
+		"last_heartbeat_at": task.LastHeartbeatAt.Format(time.RFC3339),
+		"policy":            tm.leasePolicy,
+	})
+
+	leaseErr := fmt.Errorf("lease expired: no heartbeat since %s", task.LastHeartbeatAt.Format(time.RFC3339))
+	tm.abandonAttempt(task, leaseErr, now)
+	task.ErrorCode = model.ErrorCodeLeaseExpired
+
+	// повтор после потери аренды расходует попытку, как и любой другой повтор
+	if tm.leasePolicy == LeasePolicyRequeue && task.Attempt < task.RetryPolicy.MaxAttempts {
+		task.Status = model.StatusPending
+		task.Error = leaseErr.Error()
+		tm.repo.Update(task)
+		tm.logEvent(task, model.LogLevelWarn, "attempt %d lost its lease, task requeued", task.Attempt)
+		logger.Warn("Task lease expired, task requeued")
+		return false
+	}
+
+	tm.failTask(task, leaseErr, logger)
+	return true
+}
+
+// abandonAttempt отбирает попытку у исполнителя, не дожидаясь его возврата.
+// FinishedAt отмечает попытку завершенной: если исполнитель все же вернется,
+// его результат будет отброшен; вызывается под mutex
+func (tm *TaskManager) abandonAttempt(task *model.Task, reason error, now time.Time) {
+	if cancel, exists := tm.cancels[task.ID]; exists {
+		cancel()
+	}
+	delete(tm.cancels, task.ID)
+	tm.releaseConcurrencyKey(task)
+
+	attempt := &task.Attempts[len(task.Attempts)-1]
+	attempt.FinishedAt = &now
+	attempt.Error = reason.Error()
+
+	task.LeaseExpiresAt = nil
+}
+
+// attemptCurrent сообщает, что попытка attempt все еще выполняется; вызывается под mutex
+func attemptCurrent(task *model.Task, attempt int) bool {
+	return task.Attempt == attempt && task.IsRunning() && !attemptReaped(task, attempt)
+}
+
+func staleAttemptError(task *model.Task, attempt int) error {
+	return fmt.Errorf("attempt %d of task %s is no longer running", attempt, task.ID)
+}
+
+// attemptReaped сообщает, что попытку уже забрал reaper или Shutdown; вызывается под mutex
+func attemptReaped(task *model.Task, attempt int) bool {
+	return task.Attempts[attempt-1].FinishedAt != nil
+}
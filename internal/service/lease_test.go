@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_LeaseExpiredFails(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       1,
+		LeaseDuration: 80 * time.Millisecond,
+	})
+
+	// исполнитель зависает и не реагирует на отмену контекста по таймауту
+	release := make(chan struct{})
+	manager.RegisterExecutor("hang", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "late", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "hung-1", Type: "hang", Timeout: 20 * time.Millisecond})
+	task := waitForStatus(t, manager, "hung-1", model.StatusFailed)
+
+	if task.ErrorCode != model.ErrorCodeLeaseExpired || !strings.Contains(task.Error, "lease expired") {
+		t.Errorf("Error = %q (%s), want lease expired", task.Error, task.ErrorCode)
+	}
+
+	if task.LastHeartbeatAt == nil || task.LeaseExpiresAt != nil {
+		t.Errorf("LastHeartbeatAt = %v, LeaseExpiresAt = %v, want heartbeat without lease", task.LastHeartbeatAt, task.LeaseExpiresAt)
+	}
+
+	// поздний результат зависшей попытки отбрасывается
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+
+	task, _ = manager.GetTask("hung-1")
+	if task.Status != model.StatusFailed || task.Result != nil {
+		t.Errorf("Task = %s with result %s, want failed without result", task.Status, task.Result)
+	}
+
+	if err := manager.DeleteTask("hung-1"); err != nil {
+		t.Errorf("DeleteTask() error = %v, want nil", err)
+	}
+}
+
+func TestTaskManager_LeaseExpiredRequeues(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       2,
+		LeaseDuration: 80 * time.Millisecond,
+		LeasePolicy:   LeasePolicyRequeue,
+	})
+
+	release := make(chan struct{})
+	defer close(release)
+	manager.RegisterExecutor("flaky-hang", func(ctx context.Context, task *model.Task) (any, error) {
+		if task.Attempt == 1 {
+			<-release
+		}
+		return "ok", nil
+	})
+
+	manager.CreateTask(TaskSpec{
+		ID:          "hung-2",
+		Type:        "flaky-hang",
+		Timeout:     20 * time.Millisecond,
+		RetryPolicy: &model.RetryPolicy{MaxAttempts: 2},
+	})
+	task := waitForStatus(t, manager, "hung-2", model.StatusCompleted)
+
+	if task.Attempt != 2 || !strings.Contains(task.Attempts[0].Error, "lease expired") {
+		t.Errorf("Attempt = %d, first attempt error = %q, want second attempt after lease expiry", task.Attempt, task.Attempts[0].Error)
+	}
+}
+
+func TestTaskManager_HeartbeatKeepsLease(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       1,
+		LeaseDuration: 80 * time.Millisecond,
+	})
+
+	manager.RegisterExecutor("steady", func(ctx context.Context, task *model.Task) (any, error) {
+		lease := LeaseFromContext(ctx)
+		for i := 0; i < 10; i++ {
+			time.Sleep(25 * time.Millisecond)
+			if err := lease.Heartbeat(); err != nil {
+				return nil, err
+			}
+		}
+		return "done", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "steady-1", Type: "steady"})
+	task := waitForStatus(t, manager, "steady-1", model.StatusCompleted)
+
+	if task.Attempt != 1 || task.LastHeartbeatAt == nil || !task.LastHeartbeatAt.After(*task.StartedAt) {
+		t.Errorf("Attempt = %d, LastHeartbeatAt = %v, want one attempt with heartbeats", task.Attempt, task.LastHeartbeatAt)
+	}
+}
+
+func TestTaskManager_LeaseRequeueUsesAttempts(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       3,
+		LeaseDuration: 80 * time.Millisecond,
+		LeasePolicy:   LeasePolicyRequeue,
+	})
+
+	release := make(chan struct{})
+	defer close(release)
+	manager.RegisterExecutor("always-hang", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "late", nil
+	})
+
+	manager.CreateTask(TaskSpec{
+		ID:          "hung-3",
+		Type:        "always-hang",
+		Timeout:     20 * time.Millisecond,
+		RetryPolicy: &model.RetryPolicy{MaxAttempts: 2},
+	})
+	task := waitForStatus(t, manager, "hung-3", model.StatusFailed)
+
+	if task.Attempt != 2 || task.ErrorCode != model.ErrorCodeLeaseExpired {
+		t.Errorf("Attempt = %d, ErrorCode = %q, want failure with %s after 2 attempts", task.Attempt, task.ErrorCode, model.ErrorCodeLeaseExpired)
+	}
+}
+
+func TestTaskManager_WorkerKeepsLease(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       1,
+		LeaseDuration: 80 * time.Millisecond,
+	})
+
+	// исполнитель работает дольше срока аренды, но сам heartbeat не вызывает
+	manager.RegisterExecutor("slow", func(ctx context.Context, task *model.Task) (any, error) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	manager.CreateTask(TaskSpec{ID: "slow-1", Type: "slow", Timeout: time.Second})
+	task := waitForStatus(t, manager, "slow-1", model.StatusCompleted)
+
+	if task.Attempt != 1 || task.ErrorCode != "" {
+		t.Errorf("Attempt = %d, ErrorCode = %q, want one successful attempt", task.Attempt, task.ErrorCode)
+	}
+}
+
+func TestTaskManager_LeaseExpiresWithoutTimeout(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       1,
+		LeaseDuration: 80 * time.Millisecond,
+	})
+
+	// без таймаута воркер аренду не продлевает, а исполнитель не вызывает heartbeat
+	release := make(chan struct{})
+	defer close(release)
+	manager.RegisterExecutor("hang", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "late", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "hung-4", Type: "hang"})
+	task := waitForStatus(t, manager, "hung-4", model.StatusFailed)
+
+	if task.ErrorCode != model.ErrorCodeLeaseExpired {
+		t.Errorf("ErrorCode = %q, want %s", task.ErrorCode, model.ErrorCodeLeaseExpired)
+	}
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bambutcha/taskflow/internal/model"
@@ -11,53 +15,319 @@ import (
 )
 
 type TaskManager struct {
-	repo       repository.TaskRepository
-	workerPool chan *model.Task
-	workers    int
-	testMode   bool
-	logger     *logrus.Logger
+	repo             repository.TaskRepository
+	pools            map[string]*workerPool
+	limiter          *rateLimiter
+	ids              idGenerator
+	scheduler        *scheduler
+	workerMutex      sync.Mutex
+	defaultTimeout   time.Duration
+	progressInterval time.Duration
+	retryPolicy      model.RetryPolicy
+	testMode         bool
+	executors        *executorRegistry
+	cancels          map[string]context.CancelFunc
+	keys             map[string]*concurrencyKey
+	mutex            sync.Mutex
+	schedules        repository.ScheduleRepository
+	scheduleWake     chan struct{}
+	scheduleMutex    sync.Mutex
+	workflows        repository.WorkflowRepository
+	workflowMutex    sync.Mutex
+	batches          repository.BatchRepository
+	batchMutex       sync.Mutex
+	idempotency      repository.IdempotencyRepository
+	idempotencyTTL   time.Duration
+	idempotencyMutex sync.Mutex
+	logs             map[string]*taskLog
+	taskLogSize      int
+	logMutex         sync.Mutex
+	artifacts        repository.ArtifactStore
+	maxResultSize    int
+	leaseDuration    time.Duration
+	leasePolicy      LeasePolicy
+	shutdownPolicy   ShutdownPolicy
+	workerGroup      sync.WaitGroup
+	closing          atomic.Bool
+	paused           atomic.Bool
+	done             chan struct{}
+	logger           *logrus.Logger
 }
 
-func NewTaskManager(repo repository.TaskRepository, workers int) *TaskManager {
+type Options struct {
+	Workers          int
+	DefaultTimeout   time.Duration
+	RetryPolicy      model.RetryPolicy
+	PriorityAging    time.Duration
+	Schedules        repository.ScheduleRepository
+	Workflows        repository.WorkflowRepository
+	Batches          repository.BatchRepository
+	Idempotency      repository.IdempotencyRepository
+	IdempotencyTTL   time.Duration
+	ProgressInterval time.Duration
+	TaskLogSize      int
+	Artifacts        repository.ArtifactStore
+	MaxResultSize    int
+	LeaseDuration    time.Duration
+	LeasePolicy      LeasePolicy
+	ShutdownPolicy   ShutdownPolicy
+	// Queues задает число воркеров именованных очередей; очередь
+	// DefaultQueue всегда существует и по умолчанию получает Workers воркеров
+	Queues     map[string]int
+	RateLimits []RateLimit
+}
+
+type TaskSpec struct {
+	ID               string
+	IDPrefix         string
+	Type             string
+	Payload          json.RawMessage
+	Priority         int
+	RunAt            time.Time
+	Timeout          time.Duration
+	RetryPolicy      *model.RetryPolicy
+	DependsOn        []string
+	ScheduleID       string
+	WorkflowID       string
+	WorkflowStep     string
+	BatchID          string
+	Queue            string
+	ConcurrencyKey   string
+	ConcurrencyLimit int
+}
+
+func NewTaskManager(repo repository.TaskRepository, opts Options) *TaskManager {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
-	manager := &TaskManager{
-		repo:       repo,
-		workerPool: make(chan *model.Task, workers*2),
-		workers:    workers,
-		testMode:   false,
-		logger:     logger,
-	}
+	manager := newTaskManager(repo, opts, logger)
 
-	manager.startWorkers()
-	manager.logger.WithField("workers", workers).Info("TaskManager initialized")
+	manager.start()
+	manager.logger.WithFields(logrus.Fields{
+		"workers":         opts.Workers,
+		"default_timeout": opts.DefaultTimeout.String(),
+	}).Info("TaskManager initialized")
 	return manager
 }
 
 func NewTaskManagerForTesting(repo repository.TaskRepository, workers int) *TaskManager {
+	return NewTaskManagerForTestingWithOptions(repo, Options{Workers: workers})
+}
+
+func NewTaskManagerForTestingWithOptions(repo repository.TaskRepository, opts Options) *TaskManager {
 	logger := logrus.New()
 	logger.SetLevel(logrus.WarnLevel)
 
+	manager := newTaskManager(repo, opts, logger)
+	manager.testMode = true
+
+	manager.start()
+	return manager
+}
+
+func newTaskManager(repo repository.TaskRepository, opts Options, logger *logrus.Logger) *TaskManager {
 	manager := &TaskManager{
-		repo:       repo,
-		workerPool: make(chan *model.Task, workers*2),
-		workers:    workers,
-		testMode:   true,
-		logger:     logger,
+		repo:             repo,
+		pools:            newWorkerPools(opts),
+		limiter:          newRateLimiter(),
+		defaultTimeout:   opts.DefaultTimeout,
+		retryPolicy:      mergeRetryPolicy(&opts.RetryPolicy, DefaultRetryPolicy),
+		executors:        newExecutorRegistry(),
+		cancels:          make(map[string]context.CancelFunc),
+		keys:             make(map[string]*concurrencyKey),
+		schedules:        opts.Schedules,
+		scheduleWake:     make(chan struct{}, 1),
+		workflows:        opts.Workflows,
+		batches:          opts.Batches,
+		idempotency:      opts.Idempotency,
+		idempotencyTTL:   opts.IdempotencyTTL,
+		progressInterval: opts.ProgressInterval,
+		logs:             make(map[string]*taskLog),
+		taskLogSize:      opts.TaskLogSize,
+		artifacts:        opts.Artifacts,
+		maxResultSize:    opts.MaxResultSize,
+		leaseDuration:    opts.LeaseDuration,
+		leasePolicy:      opts.LeasePolicy,
+		shutdownPolicy:   opts.ShutdownPolicy,
+		done:             make(chan struct{}),
+		logger:           logger,
+	}
+
+	if manager.schedules == nil {
+		manager.schedules = repository.NewMemoryScheduleRepository()
+	}
+
+	if manager.workflows == nil {
+		manager.workflows = repository.NewMemoryWorkflowRepository()
+	}
+
+	if manager.batches == nil {
+		manager.batches = repository.NewMemoryBatchRepository()
+	}
+
+	if manager.idempotency == nil {
+		manager.idempotency = repository.NewMemoryIdempotencyRepository()
+	}
+
+	if manager.artifacts == nil {
+		manager.artifacts = repository.NewMemoryArtifactStore()
+	}
+
+	if manager.idempotencyTTL <= 0 {
+		manager.idempotencyTTL = DefaultIdempotencyTTL
+	}
+
+	if manager.progressInterval <= 0 {
+		manager.progressInterval = DefaultProgressInterval
+	}
+
+	if manager.taskLogSize <= 0 {
+		manager.taskLogSize = DefaultTaskLogSize
+	}
+
+	if manager.maxResultSize <= 0 {
+		manager.maxResultSize = DefaultMaxResultSize
 	}
 
-	manager.startWorkers()
+	if manager.leaseDuration <= 0 {
+		manager.leaseDuration = DefaultLeaseDuration
+	}
+
+	switch manager.leasePolicy {
+	case LeasePolicyFail, LeasePolicyRequeue:
+	case "":
+		manager.leasePolicy = LeasePolicyFail
+	default:
+		logger.WithField("policy", manager.leasePolicy).Error("Unknown lease policy, using fail")
+		manager.leasePolicy = LeasePolicyFail
+	}
+
+	switch manager.shutdownPolicy {
+	case ShutdownRequeue, ShutdownFail:
+	case "":
+		manager.shutdownPolicy = ShutdownRequeue
+	default:
+		logger.WithField("policy", manager.shutdownPolicy).Error("Unknown shutdown policy, using requeue")
+		manager.shutdownPolicy = ShutdownRequeue
+	}
+
+	for _, limit := range opts.RateLimits {
+		if err := manager.limiter.add(limit, time.Now()); err != nil {
+			logger.WithFields(logrus.Fields{
+				"scope": limit.Scope,
+				"name":  limit.Name,
+				"error": err.Error(),
+			}).Error("Skipping invalid rate limit")
+		}
+	}
+
+	manager.scheduler = newScheduler(manager.releaseDue)
+	manager.executors.register(DefaultTaskType, manager.sleepExecutor)
+	manager.loadSchedules()
 	return manager
 }
 
-func (tm *TaskManager) CreateTask(id string) (*model.Task, error) {
+func (tm *TaskManager) start() {
+	tm.startWorkers()
+	go tm.scheduler.run()
+	go tm.runSchedules()
+	go tm.purgeIdempotencyKeys()
+	go tm.reapLeases()
+}
+
+func (tm *TaskManager) CreateTask(spec TaskSpec) (*model.Task, error) {
+	if tm.isClosing() {
+		tm.logger.WithField("task_id", spec.ID).Warn("Rejecting task: task manager is shutting down")
+		return nil, ErrShuttingDown
+	}
+
+	return tm.createTask(spec)
+}
+
+// createTask создает задачу, не проверяя остановку. Через него задачи порождают
+// workflow, группы и расписания: во время Shutdown такая задача сохраняется
+// pending и не попадает в закрытую очередь
+func (tm *TaskManager) createTask(spec TaskSpec) (*model.Task, error) {
+	// префикс используется, только если ID не передан
+	if spec.ID == "" {
+		if err := validateIDPrefix(spec.IDPrefix); err != nil {
+			tm.logger.WithFields(logrus.Fields{
+				"id_prefix": spec.IDPrefix,
+				"error":     err.Error(),
+			}).Warn("Invalid task specification")
+			return nil, err
+		}
+		spec.ID = tm.generateTaskID(spec.IDPrefix)
+	}
+
+	id := spec.ID
 	tm.logger.WithField("task_id", id).Info("Creating task")
 
+	err := tm.validateSpec(&spec)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": id,
+			"error":   err.Error(),
+		}).Warn("Invalid task specification")
+		return nil, err
+	}
+
 	task := model.NewTask(id)
+	task.Type = spec.Type
+	task.ScheduleID = spec.ScheduleID
+	task.WorkflowID = spec.WorkflowID
+	task.WorkflowStep = spec.WorkflowStep
+	task.BatchID = spec.BatchID
+	task.Queue = spec.Queue
+	task.ConcurrencyKey = spec.ConcurrencyKey
+	task.ConcurrencyLimit = spec.ConcurrencyLimit
+	task.Payload = spec.Payload
+	task.Priority = spec.Priority
+	task.Timeout = model.Duration(spec.Timeout)
+
+	retryPolicy := mergeRetryPolicy(spec.RetryPolicy, tm.retryPolicy)
+	task.RetryPolicy = &retryPolicy
+
+	if spec.RunAt.After(task.CreatedAt) {
+		runAt := spec.RunAt
+		task.RunAt = &runAt
+		task.Status = model.StatusScheduled
+	}
+
+	tm.mutex.Lock()
+
+	parents, err := tm.lookupDependencies(id, spec.DependsOn)
+	if err != nil {
+		tm.mutex.Unlock()
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": id,
+			"error":   err.Error(),
+		}).Warn("Invalid task dependencies")
+		return nil, err
+	}
+
+	var dependencyErr error
+	if len(parents) > 0 {
+		for _, parent := range parents {
+			task.DependsOn = append(task.DependsOn, parent.ID)
+		}
+
+		var ready bool
+		ready, dependencyErr = tm.dependencyState(task)
+		if dependencyErr != nil {
+			completedAt := time.Now()
+			task.Status = model.StatusFailed
+			task.CompletedAt = &completedAt
+			task.Error = dependencyErr.Error()
+			task.ErrorCode = model.ErrorCodeDependencyFailed
+		} else if !ready {
+			task.Status = model.StatusBlocked
+		}
+	}
 
-	err := tm.repo.Create(task)
+	err = tm.repo.Create(task)
 	if err != nil {
+		tm.mutex.Unlock()
 		tm.logger.WithFields(logrus.Fields{
 			"task_id": id,
 			"error":   err.Error(),
@@ -65,17 +335,148 @@ func (tm *TaskManager) CreateTask(id string) (*model.Task, error) {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	select {
-	case tm.workerPool <- task:
-		tm.logger.WithField("task_id", id).Info("Task queued for execution")
+	for _, parent := range parents {
+		parent.Dependents = append(parent.Dependents, task.ID)
+		tm.repo.Update(parent)
+	}
+
+	tm.logEvent(task, model.LogLevelInfo, "task created with status %s in queue %s", task.Status, task.Queue)
+	if dependencyErr != nil {
+		tm.logEvent(task, model.LogLevelError, "task failed: %s", dependencyErr)
+	}
+
+	tm.mutex.Unlock()
+
+	switch task.Status {
+	case model.StatusBlocked:
+		tm.logger.WithFields(logrus.Fields{
+			"task_id":    id,
+			"depends_on": task.DependsOn,
+		}).Info("Task blocked until dependencies complete")
+	case model.StatusFailed:
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": id,
+			"error":   dependencyErr.Error(),
+		}).Warn("Task failed: dependency did not complete")
+		tm.taskFinished(task)
 	default:
-		tm.logger.WithField("task_id", id).Warn("Worker pool full, task will be processed later")
+		tm.dispatch(task)
 	}
 
 	tm.logger.WithField("task_id", id).Info("Task created successfully")
-	return task, nil
+	return tm.snapshotTask(task), nil
 }
 
+// dispatch передает готовую к запуску задачу планировщику или в очередь
+func (tm *TaskManager) dispatch(task *model.Task) {
+	if task.Status == model.StatusScheduled {
+		tm.scheduler.add(*task.RunAt, task)
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": task.ID,
+			"run_at":  task.RunAt.Format(time.RFC3339),
+		}).Info("Task scheduled for delayed execution")
+		return
+	}
+
+	tm.enqueue(task)
+}
+
+// validateSpec проверяет параметры задачи и подставляет тип по умолчанию
+func (tm *TaskManager) validateSpec(spec *TaskSpec) error {
+	if spec.Type == "" {
+		spec.Type = DefaultTaskType
+	}
+
+	if spec.Priority < model.MinPriority || spec.Priority > model.MaxPriority {
+		return validationErrorf("invalid priority: must be between %d and %d", model.MinPriority, model.MaxPriority)
+	}
+
+	if spec.Timeout < 0 {
+		return validationErrorf("invalid timeout: must not be negative")
+	}
+
+	if spec.RetryPolicy != nil {
+		if err := validateRetryPolicy(*spec.RetryPolicy); err != nil {
+			return err
+		}
+	}
+
+	if !tm.HasExecutor(spec.Type) {
+		return validationErrorf("unknown task type: %s", spec.Type)
+	}
+
+	if spec.ConcurrencyLimit < 0 {
+		return validationErrorf("invalid concurrency limit: must not be negative")
+	}
+
+	if spec.ConcurrencyLimit > 0 && spec.ConcurrencyKey == "" {
+		return validationErrorf("invalid concurrency limit: concurrency key is required")
+	}
+
+	if spec.ConcurrencyKey != "" && spec.ConcurrencyLimit == 0 {
+		spec.ConcurrencyLimit = 1
+	}
+
+	if spec.Queue == "" {
+		spec.Queue = DefaultQueue
+	}
+
+	if _, exists := tm.pools[spec.Queue]; !exists {
+		return validationErrorf("unknown queue: %s", spec.Queue)
+	}
+
+	return nil
+}
+
+// releaseDue вызывается планировщиком, когда наступает время запуска
+// отложенной задачи или повтора после ошибки
+func (tm *TaskManager) releaseDue(task *model.Task) {
+	tm.mutex.Lock()
+
+	if _, err := tm.repo.GetByID(task.ID); err != nil {
+		tm.mutex.Unlock()
+		tm.logger.WithField("task_id", task.ID).Debug("Skipping due task: deleted")
+		return
+	}
+
+	switch task.Status {
+	case model.StatusScheduled:
+		task.Status = model.StatusPending
+		err := tm.repo.Update(task)
+		if err != nil {
+			tm.mutex.Unlock()
+			tm.logger.WithFields(logrus.Fields{
+				"task_id": task.ID,
+				"error":   err.Error(),
+			}).Error("Failed to update due task")
+			return
+		}
+	case model.StatusPending:
+	default:
+		tm.mutex.Unlock()
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": task.ID,
+			"status":  task.Status,
+		}).Debug("Skipping due task that is no longer waiting")
+		return
+	}
+
+	tm.mutex.Unlock()
+	tm.enqueue(task)
+}
+
+func (tm *TaskManager) enqueue(task *model.Task) {
+	pool := tm.pools[task.Queue]
+	pool.queue.push(task)
+	tm.logger.WithFields(logrus.Fields{
+		"task_id":     task.ID,
+		"queue":       task.Queue,
+		"priority":    task.Priority,
+		"queue_depth": pool.queue.len(),
+	}).Info("Task queued for execution")
+}
+
+// GetTask возвращает копию задачи: поля задачи меняются воркерами под mutex
 func (tm *TaskManager) GetTask(id string) (*model.Task, error) {
 	tm.logger.WithField("task_id", id).Debug("Getting task")
 
@@ -88,31 +489,56 @@ func (tm *TaskManager) GetTask(id string) (*model.Task, error) {
 		return nil, err
 	}
 
-	return task, nil
+	return tm.snapshotTask(task), nil
+}
+
+// snapshotTask копирует задачу под mutex
+func (tm *TaskManager) snapshotTask(task *model.Task) *model.Task {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return copyTask(task)
+}
+
+// copyTask возвращает копию задачи, которую можно читать без mutex; вызывается под mutex
+func copyTask(task *model.Task) *model.Task {
+	copied := *task
+	copied.Attempts = append([]model.Attempt(nil), task.Attempts...)
+	copied.DependsOn = append([]string(nil), task.DependsOn...)
+	copied.Dependents = append([]string(nil), task.Dependents...)
+	copied.Artifacts = append([]model.Artifact(nil), task.Artifacts...)
+	return &copied
 }
 
 func (tm *TaskManager) DeleteTask(id string) error {
 	tm.logger.WithField("task_id", id).Info("Deleting task")
 
+	// проверка статуса и удаление выполняются под mutex, чтобы воркер
+	// не успел запустить задачу между ними
+	tm.mutex.Lock()
+
 	task, err := tm.repo.GetByID(id)
 	if err != nil {
+		tm.mutex.Unlock()
 		tm.logger.WithFields(logrus.Fields{
 			"task_id": id,
 			"error":   err.Error(),
 		}).Warn("Cannot delete task: not found")
-		return fmt.Errorf("task not found: %w", err)
+		return fmt.Errorf("%w: %w", ErrTaskNotFound, err)
 	}
 
 	if task.IsRunning() {
+		tm.mutex.Unlock()
 		tm.logger.WithFields(logrus.Fields{
 			"task_id": id,
 			"status":  task.Status,
 		}).Warn("Cannot delete running task")
-		return fmt.Errorf("cannot delete running task")
+		return kindErrorf(ErrConflict, "cannot delete running task")
 	}
 
 	err = tm.repo.Delete(id)
 	if err != nil {
+		tm.mutex.Unlock()
 		tm.logger.WithFields(logrus.Fields{
 			"task_id": id,
 			"error":   err.Error(),
@@ -120,10 +546,79 @@ func (tm *TaskManager) DeleteTask(id string) error {
 		return err
 	}
 
+	// после удаления под mutex новые зависимые задачи уже не появятся
+	hasDependents := len(task.Dependents) > 0
+	tm.mutex.Unlock()
+
+	tm.dropTaskLog(id)
+	tm.deleteArtifacts(task)
+
+	if hasDependents {
+		tm.resolveDependents(task)
+	}
+
+	if task.WorkflowID != "" {
+		tm.workflowTaskDeleted(task)
+	}
+
+	if task.BatchID != "" {
+		tm.batchTaskDeleted(task)
+	}
+
 	tm.logger.WithField("task_id", id).Info("Task deleted successfully")
 	return nil
 }
 
+func (tm *TaskManager) CancelTask(id string) (*model.Task, error) {
+	tm.logger.WithField("task_id", id).Info("Cancelling task")
+
+	task, err := tm.repo.GetByID(id)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": id,
+			"error":   err.Error(),
+		}).Warn("Cannot cancel task: not found")
+		return nil, fmt.Errorf("%w: %w", ErrTaskNotFound, err)
+	}
+
+	tm.mutex.Lock()
+
+	if task.IsCompleted() {
+		tm.mutex.Unlock()
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": id,
+			"status":  task.Status,
+		}).Warn("Cannot cancel finished task")
+		return nil, kindErrorf(ErrConflict, "cannot cancel finished task")
+	}
+
+	now := time.Now()
+	task.Status = model.StatusCancelled
+	task.CancelledAt = &now
+
+	err = tm.repo.Update(task)
+	if err != nil {
+		tm.mutex.Unlock()
+		tm.logger.WithFields(logrus.Fields{
+			"task_id": id,
+			"error":   err.Error(),
+		}).Error("Failed to update cancelled task")
+		return nil, err
+	}
+
+	if cancel, exists := tm.cancels[id]; exists {
+		cancel()
+	}
+
+	tm.logEvent(task, model.LogLevelWarn, "task cancelled")
+	cancelled := copyTask(task)
+	tm.mutex.Unlock()
+	tm.taskFinished(task)
+
+	tm.logger.WithField("task_id", id).Info("Task cancelled successfully")
+	return cancelled, nil
+}
+
 func (tm *TaskManager) GetAllTasks() ([]*model.Task, error) {
 	tm.logger.Debug("Getting all tasks")
 
@@ -133,70 +628,252 @@ func (tm *TaskManager) GetAllTasks() ([]*model.Task, error) {
 		return nil, err
 	}
 
+	tm.mutex.Lock()
+	for i, task := range tasks {
+		tasks[i] = copyTask(task)
+	}
+	tm.mutex.Unlock()
+
 	tm.logger.WithField("count", len(tasks)).Debug("Retrieved all tasks")
 	return tasks, nil
 }
 
-func (tm *TaskManager) GetWorkerCount() int {
-	return tm.workers
-}
+func (tm *TaskManager) executeTask(task *model.Task, workerID int) {
+	logger := tm.logger.WithFields(logrus.Fields{
+		"task_id":   task.ID,
+		"task_type": task.Type,
+		"queue":     task.Queue,
+		"worker_id": workerID,
+	})
+
+	timeout := tm.taskTimeout(task)
 
-func (tm *TaskManager) startWorkers() {
-	for i := 0; i < tm.workers; i++ {
-		go tm.worker(i + 1)
+	ctx := context.WithValue(context.Background(), workerIDKey{}, workerID)
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
 	}
-}
+	defer cancel()
 
-func (tm *TaskManager) worker(workerID int) {
-	tm.logger.WithField("worker_id", workerID).Info("Worker started")
+	tm.mutex.Lock()
+	if !task.IsPending() {
+		tm.mutex.Unlock()
+		logger.WithField("status", task.Status).Info("Skipping task that is no longer pending")
+		return
+	}
 
-	for task := range tm.workerPool {
-		tm.executeTask(task, workerID)
+	// задача с занятым ключом остается pending и вернется в очередь,
+	// когда одна из задач с тем же ключом завершит попытку
+	if !tm.concurrencyKeyAvailable(task) {
+		tm.mutex.Unlock()
+		logger.WithField("concurrency_key", task.ConcurrencyKey).Debug("Task waits for concurrency key")
+		return
 	}
 
-	tm.logger.WithField("worker_id", workerID).Info("Worker stopped")
-}
+	now := time.Now()
 
-func (tm *TaskManager) executeTask(task *model.Task, workerID int) {
-	logger := tm.logger.WithFields(logrus.Fields{
-		"task_id":   task.ID,
-		"worker_id": workerID,
-	})
+	// задача, упершаяся в лимит, остается pending и возвращается в очередь позже
+	if wait := tm.limiter.reserve(task, now); wait > 0 {
+		tm.mutex.Unlock()
+		tm.scheduler.add(now.Add(wait), task)
+		logger.WithField("wait", wait.String()).Debug("Task throttled by rate limit")
+		return
+	}
 
 	logger.Info("Starting task execution")
 
-	now := time.Now()
 	task.Status = model.StatusRunning
 	task.StartedAt = &now
+	task.NextRetryAt = nil
+	task.Progress = nil
+	task.Attempt++
+	tm.renewLease(task, now)
+	task.Attempts = append(task.Attempts, model.Attempt{
+		Number:    task.Attempt,
+		StartedAt: now,
+	})
 
 	err := tm.repo.Update(task)
 	if err != nil {
+		tm.mutex.Unlock()
 		logger.WithField("error", err.Error()).Error("Failed to update task status to running")
 		return
 	}
 
-	logger.Info("Task status updated to running")
-
-	var duration time.Duration
-	if tm.testMode {
-		duration = 100 * time.Millisecond
+	tm.cancels[task.ID] = cancel
+	tm.acquireConcurrencyKey(task)
+	tm.logEvent(task, model.LogLevelInfo, "attempt %d started on worker %d", task.Attempt, workerID)
+	attempt := task.Attempt
+	// исполнитель получает копию: задачу, отобранную reaper, может взять другой воркер
+	view := copyTask(task)
+	tm.mutex.Unlock()
+
+	logger.WithField("attempt", attempt).Info("Task status updated to running")
+
+	lease := &Lease{tm: tm, task: task, attempt: attempt}
+	reporter := &ProgressReporter{tm: tm, task: task, lease: lease}
+	ctx = context.WithValue(ctx, progressKey{}, reporter)
+	ctx = context.WithValue(ctx, taskLoggerKey{}, &TaskLogger{log: tm.taskLog(task.ID)})
+	ctx = context.WithValue(ctx, artifactsKey{}, &Artifacts{tm: tm, task: task, attempt: attempt})
+	ctx = context.WithValue(ctx, leaseKey{}, lease)
+
+	var result any
+	executor, exists := tm.executors.get(task.Type)
+	if exists {
+		stopLease := tm.keepLease(ctx, lease)
+		result, err = executor(ctx, view)
+		stopLease()
 	} else {
-		duration = time.Duration(3+rand.Intn(3)) * time.Minute
+		err = Permanent(fmt.Errorf("unknown task type: %s", task.Type))
 	}
+	duration := time.Since(now)
+	reporter.close()
 
-	logger.WithField("duration", duration.String()).Info("Executing IO-bound operation")
-	time.Sleep(duration)
+	var errorCode string
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		errorCode = model.ErrorCodeDeadlineExceeded
+		err = fmt.Errorf("deadline exceeded: task did not finish within %s", timeout)
+	}
+
+	// результат, который не кодируется или слишком велик, не сохраняется,
+	// повтор его не исправит
+	var encoded json.RawMessage
+	if err == nil {
+		encoded, errorCode, err = tm.encodeResult(result)
+		if err != nil {
+			err = Permanent(err)
+		}
+	}
+
+	tm.mutex.Lock()
+	if attemptReaped(task, attempt) {
+		tm.mutex.Unlock()
+		logger.WithField("attempt", attempt).Warn("Discarding result of abandoned attempt")
+		return
+	}
+	finished := tm.finishAttempt(task, encoded, err, errorCode, logger.WithField("duration", duration.String()))
+	tm.mutex.Unlock()
+
+	if finished {
+		tm.taskFinished(task)
+	}
+}
+
+// finishAttempt фиксирует результат попытки и возвращает true,
+// если задача перешла в конечный статус; вызывается под mutex
+func (tm *TaskManager) finishAttempt(task *model.Task, result json.RawMessage, taskErr error, errorCode string, logger *logrus.Entry) bool {
+	delete(tm.cancels, task.ID)
+	tm.releaseConcurrencyKey(task)
+	task.LeaseExpiresAt = nil
+
+	finishedAt := time.Now()
+	attempt := &task.Attempts[len(task.Attempts)-1]
+	attempt.FinishedAt = &finishedAt
+
+	if task.Status == model.StatusCancelled {
+		attempt.Error = "cancelled"
+		tm.repo.Update(task)
+		tm.logEvent(task, model.LogLevelWarn, "attempt %d stopped after cancellation", task.Attempt)
+		logger.Info("Task execution stopped after cancellation")
+		return false
+	}
+
+	if taskErr != nil {
+		attempt.Error = taskErr.Error()
+
+		if isRetryable(taskErr) && task.Attempt < task.RetryPolicy.MaxAttempts {
+			tm.scheduleRetry(task, taskErr, errorCode, logger)
+			return false
+		}
+
+		task.ErrorCode = errorCode
+		tm.failTask(task, taskErr, logger)
+		return true
+	}
 
-	completedAt := time.Now()
 	task.Status = model.StatusCompleted
-	task.CompletedAt = &completedAt
-	task.Result = fmt.Sprintf("Task completed by worker %d", workerID)
+	task.CompletedAt = &finishedAt
+	task.Result = result
+	task.Error = ""
+	task.ErrorCode = ""
 
-	err = tm.repo.Update(task)
+	err := tm.repo.Update(task)
 	if err != nil {
 		logger.WithField("error", err.Error()).Error("Failed to update completed task")
+		return true
+	}
+
+	tm.logEvent(task, model.LogLevelInfo, "task completed")
+	logger.Info("Task completed successfully")
+	return true
+}
+
+func (tm *TaskManager) scheduleRetry(task *model.Task, taskErr error, errorCode string, logger *logrus.Entry) {
+	backoff := retryBackoff(*task.RetryPolicy, task.Attempt)
+	nextRetryAt := time.Now().Add(backoff)
+
+	task.Status = model.StatusPending
+	task.Error = taskErr.Error()
+	task.ErrorCode = errorCode
+	task.NextRetryAt = &nextRetryAt
+
+	err := tm.repo.Update(task)
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to update task scheduled for retry")
 		return
 	}
 
-	logger.WithField("duration", duration.String()).Info("Task completed successfully")
+	tm.scheduler.add(nextRetryAt, task)
+	tm.logEvent(task, model.LogLevelWarn, "attempt %d failed: %s; retry in %s", task.Attempt, taskErr, backoff)
+
+	logger.WithFields(logrus.Fields{
+		"attempt": task.Attempt,
+		"backoff": backoff.String(),
+		"error":   taskErr.Error(),
+	}).Warn("Task attempt failed, retry scheduled")
+}
+
+// taskFinished вызывается без mutex после перехода задачи в конечный статус
+func (tm *TaskManager) taskFinished(task *model.Task) {
+	// Dependents читается под mutex внутри resolveDependents: зависимая
+	// задача может добавиться одновременно с завершением родителя
+	tm.resolveDependents(task)
+
+	if task.ScheduleID != "" {
+		tm.scheduledTaskFinished(task)
+	}
+
+	if task.WorkflowID != "" {
+		tm.workflowTaskFinished(task)
+	}
+
+	if task.BatchID != "" {
+		tm.batchTaskFinished(task)
+	}
+}
+
+func (tm *TaskManager) taskTimeout(task *model.Task) time.Duration {
+	if task.Timeout > 0 {
+		return task.Timeout.Duration()
+	}
+	return tm.defaultTimeout
+}
+
+func (tm *TaskManager) failTask(task *model.Task, taskErr error, logger *logrus.Entry) {
+	completedAt := time.Now()
+	task.Status = model.StatusFailed
+	task.CompletedAt = &completedAt
+	task.Error = taskErr.Error()
+
+	err := tm.repo.Update(task)
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to update failed task")
+		return
+	}
+
+	tm.logEvent(task, model.LogLevelError, "task failed: %s", taskErr)
+
+	logger.WithField("error", taskErr.Error()).Warn("Task failed")
 }
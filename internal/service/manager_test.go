@@ -1,7 +1,12 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,7 +18,7 @@ func TestTaskManager_CreateTask(t *testing.T) {
 	repo := repository.NewMemoryRepository()
 	manager := NewTaskManagerForTesting(repo, 2)
 
-	task, err := manager.CreateTask("test-1")
+	task, err := manager.CreateTask(TaskSpec{ID: "test-1"})
 	if err != nil {
 		t.Errorf("CreateTask() error = %v, want nil", err)
 	}
@@ -40,12 +45,12 @@ func TestTaskManager_CreateTask_Duplicate(t *testing.T) {
 	repo := repository.NewMemoryRepository()
 	manager := NewTaskManagerForTesting(repo, 2)
 
-	_, err := manager.CreateTask("test-1")
+	_, err := manager.CreateTask(TaskSpec{ID: "test-1"})
 	if err != nil {
 		t.Errorf("First CreateTask() error = %v, want nil", err)
 	}
 
-	_, err = manager.CreateTask("test-1")
+	_, err = manager.CreateTask(TaskSpec{ID: "test-1"})
 	if err == nil {
 		t.Error("Second CreateTask() error = nil, want error")
 	}
@@ -55,7 +60,7 @@ func TestTaskManager_DeleteTask(t *testing.T) {
 	repo := repository.NewMemoryRepository()
 	manager := NewTaskManagerForTesting(repo, 0)
 
-	_, err := manager.CreateTask("test-1")
+	_, err := manager.CreateTask(TaskSpec{ID: "test-1"})
 	if err != nil {
 		t.Errorf("CreateTask() error = %v, want nil", err)
 	}
@@ -85,7 +90,7 @@ func TestTaskManager_TaskExecution(t *testing.T) {
 	repo := repository.NewMemoryRepository()
 	manager := NewTaskManagerForTesting(repo, 1)
 
-	_, err := manager.CreateTask("test-execution")
+	_, err := manager.CreateTask(TaskSpec{ID: "test-execution"})
 	if err != nil {
 		t.Errorf("CreateTask() error = %v, want nil", err)
 	}
@@ -101,11 +106,541 @@ func TestTaskManager_TaskExecution(t *testing.T) {
 		t.Errorf("Task status = %v, want %v", updatedTask.Status, model.StatusCompleted)
 	}
 
-	if updatedTask.Result == "" {
+	if len(updatedTask.Result) == 0 {
 		t.Error("Task result is empty, expected some result")
 	}
 
-	if !strings.Contains(updatedTask.Result, "completed by worker") {
+	if !strings.Contains(string(updatedTask.Result), "completed by worker") {
 		t.Errorf("Task result doesn't contain expected text: %s", updatedTask.Result)
 	}
 }
+
+func waitForStatus(t *testing.T, manager *TaskManager, id string, status model.TaskStatus) *model.Task {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		task, err := manager.GetTask(id)
+		if err == nil && task.Status == status {
+			return task
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	task, _ := manager.GetTask(id)
+	if task != nil {
+		t.Fatalf("Task %s status = %v, want %v", id, task.Status, status)
+	}
+	t.Fatalf("Task %s not found, want status %v", id, status)
+	return nil
+}
+
+func TestTaskManager_RegisteredExecutor(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	err := manager.RegisterExecutor("echo", func(ctx context.Context, task *model.Task) (any, error) {
+		return "echo " + task.ID, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterExecutor() error = %v, want nil", err)
+	}
+
+	_, err = manager.CreateTask(TaskSpec{ID: "test-echo", Type: "echo"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	task := waitForStatus(t, manager, "test-echo", model.StatusCompleted)
+	if string(task.Result) != `"echo test-echo"` {
+		t.Errorf("Task result = %s, want %q", task.Result, "echo test-echo")
+	}
+}
+
+func TestTaskManager_ExecutorError(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.RegisterExecutor("broken", func(ctx context.Context, task *model.Task) (any, error) {
+		return nil, errors.New("disk is full")
+	})
+
+	_, err := manager.CreateTask(TaskSpec{ID: "test-broken", Type: "broken"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	task := waitForStatus(t, manager, "test-broken", model.StatusFailed)
+	if task.Error != "disk is full" {
+		t.Errorf("Task error = %q, want %q", task.Error, "disk is full")
+	}
+
+	if task.CompletedAt == nil {
+		t.Error("Task CompletedAt is nil for failed task")
+	}
+}
+
+func TestTaskManager_CreateTask_UnknownType(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	_, err := manager.CreateTask(TaskSpec{ID: "test-unknown", Type: "missing"})
+	if err == nil {
+		t.Fatal("CreateTask() error = nil, want error")
+	}
+
+	if _, err := manager.GetTask("test-unknown"); err == nil {
+		t.Error("GetTask() error = nil, task with unknown type must not be stored")
+	}
+}
+
+func TestTaskManager_PayloadPassedToExecutor(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.RegisterExecutor("import", func(ctx context.Context, task *model.Task) (any, error) {
+		var params struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(task.Payload, &params); err != nil {
+			return nil, err
+		}
+		return "imported " + params.Path, nil
+	})
+
+	_, err := manager.CreateTask(TaskSpec{
+		ID:      "test-import",
+		Type:    "import",
+		Payload: json.RawMessage(`{"path":"/data/customers.csv"}`),
+	})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	task := waitForStatus(t, manager, "test-import", model.StatusCompleted)
+	if string(task.Result) != `"imported /data/customers.csv"` {
+		t.Errorf("Task result = %s, want %q", task.Result, "imported /data/customers.csv")
+	}
+}
+
+func TestTaskManager_CancelRunningTask(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	stopped := make(chan struct{})
+	manager.RegisterExecutor("blocking", func(ctx context.Context, task *model.Task) (any, error) {
+		<-ctx.Done()
+		close(stopped)
+		return nil, ctx.Err()
+	})
+
+	_, err := manager.CreateTask(TaskSpec{ID: "test-cancel", Type: "blocking"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	waitForStatus(t, manager, "test-cancel", model.StatusRunning)
+
+	task, err := manager.CancelTask("test-cancel")
+	if err != nil {
+		t.Fatalf("CancelTask() error = %v, want nil", err)
+	}
+
+	if task.Status != model.StatusCancelled {
+		t.Errorf("CancelTask() Status = %v, want %v", task.Status, model.StatusCancelled)
+	}
+
+	if task.CancelledAt == nil {
+		t.Error("CancelTask() CancelledAt is nil")
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Executor context was not cancelled")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	task, _ = manager.GetTask("test-cancel")
+	if task.Status != model.StatusCancelled {
+		t.Errorf("Task status after executor exit = %v, want %v", task.Status, model.StatusCancelled)
+	}
+}
+
+func TestTaskManager_CancelPendingTask(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	manager.RegisterExecutor("gated", func(ctx context.Context, task *model.Task) (any, error) {
+		started <- task.ID
+		<-release
+		return "done", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "test-first", Type: "gated"})
+	manager.CreateTask(TaskSpec{ID: "test-second", Type: "gated"})
+
+	if id := <-started; id != "test-first" {
+		t.Fatalf("First started task = %s, want test-first", id)
+	}
+
+	_, err := manager.CancelTask("test-second")
+	if err != nil {
+		t.Fatalf("CancelTask() error = %v, want nil", err)
+	}
+
+	close(release)
+	waitForStatus(t, manager, "test-first", model.StatusCompleted)
+
+	select {
+	case id := <-started:
+		t.Errorf("Cancelled task %s was executed", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	task, _ := manager.GetTask("test-second")
+	if task.Status != model.StatusCancelled {
+		t.Errorf("Task status = %v, want %v", task.Status, model.StatusCancelled)
+	}
+}
+
+func TestTaskManager_CancelFinishedTask(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.CreateTask(TaskSpec{ID: "test-finished"})
+	waitForStatus(t, manager, "test-finished", model.StatusCompleted)
+
+	_, err := manager.CancelTask("test-finished")
+	if err == nil {
+		t.Error("CancelTask() error = nil, want error")
+	}
+}
+
+func TestTaskManager_TaskTimeout(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.RegisterExecutor("slow", func(ctx context.Context, task *model.Task) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	_, err := manager.CreateTask(TaskSpec{ID: "test-timeout", Type: "slow", Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	task := waitForStatus(t, manager, "test-timeout", model.StatusFailed)
+	if task.ErrorCode != model.ErrorCodeDeadlineExceeded {
+		t.Errorf("Task error code = %q, want %q", task.ErrorCode, model.ErrorCodeDeadlineExceeded)
+	}
+
+	if !strings.Contains(task.Error, "deadline exceeded") {
+		t.Errorf("Task error doesn't mention deadline: %s", task.Error)
+	}
+}
+
+func TestTaskManager_DefaultTimeout(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:        1,
+		DefaultTimeout: 20 * time.Millisecond,
+	})
+
+	_, err := manager.CreateTask(TaskSpec{ID: "test-default-timeout"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	task := waitForStatus(t, manager, "test-default-timeout", model.StatusFailed)
+	if task.ErrorCode != model.ErrorCodeDeadlineExceeded {
+		t.Errorf("Task error code = %q, want %q", task.ErrorCode, model.ErrorCodeDeadlineExceeded)
+	}
+
+	_, err = manager.CreateTask(TaskSpec{ID: "test-own-timeout", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	waitForStatus(t, manager, "test-own-timeout", model.StatusCompleted)
+}
+
+func TestTaskManager_RetryUntilSuccess(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	calls := 0
+	manager.RegisterExecutor("flaky", func(ctx context.Context, task *model.Task) (any, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.New("temporary failure")
+		}
+		return "ok", nil
+	})
+
+	_, err := manager.CreateTask(TaskSpec{
+		ID:   "test-flaky",
+		Type: "flaky",
+		RetryPolicy: &model.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: model.Duration(10 * time.Millisecond),
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	task := waitForStatus(t, manager, "test-flaky", model.StatusCompleted)
+
+	if task.Attempt != 3 {
+		t.Errorf("Task attempt = %d, want 3", task.Attempt)
+	}
+
+	if len(task.Attempts) != 3 {
+		t.Fatalf("Attempts history length = %d, want 3", len(task.Attempts))
+	}
+
+	if task.Attempts[0].Error != "temporary failure" {
+		t.Errorf("First attempt error = %q, want %q", task.Attempts[0].Error, "temporary failure")
+	}
+
+	if task.Attempts[2].Error != "" || task.Attempts[2].FinishedAt == nil {
+		t.Errorf("Last attempt = %+v, want finished without error", task.Attempts[2])
+	}
+
+	if task.Error != "" || task.NextRetryAt != nil {
+		t.Errorf("Completed task keeps retry state: error=%q next_retry_at=%v", task.Error, task.NextRetryAt)
+	}
+}
+
+func TestTaskManager_RetryExhausted(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers: 1,
+		RetryPolicy: model.RetryPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: model.Duration(10 * time.Millisecond),
+		},
+	})
+
+	manager.RegisterExecutor("broken", func(ctx context.Context, task *model.Task) (any, error) {
+		return nil, errors.New("still broken")
+	})
+
+	manager.CreateTask(TaskSpec{ID: "test-exhausted", Type: "broken"})
+
+	task := waitForStatus(t, manager, "test-exhausted", model.StatusFailed)
+	if task.Attempt != 2 {
+		t.Errorf("Task attempt = %d, want 2", task.Attempt)
+	}
+}
+
+func TestTaskManager_PermanentErrorNotRetried(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.RegisterExecutor("invalid", func(ctx context.Context, task *model.Task) (any, error) {
+		return nil, Permanent(errors.New("malformed payload"))
+	})
+
+	manager.CreateTask(TaskSpec{
+		ID:          "test-permanent",
+		Type:        "invalid",
+		RetryPolicy: &model.RetryPolicy{MaxAttempts: 5},
+	})
+
+	task := waitForStatus(t, manager, "test-permanent", model.StatusFailed)
+	if task.Attempt != 1 {
+		t.Errorf("Task attempt = %d, want 1", task.Attempt)
+	}
+}
+
+func TestTaskManager_FloodBeyondWorkerCapacity(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	const total = 500
+
+	var executed []string
+	var mutex sync.Mutex
+	manager.RegisterExecutor("record", func(ctx context.Context, task *model.Task) (any, error) {
+		mutex.Lock()
+		executed = append(executed, task.ID)
+		mutex.Unlock()
+		return "ok", nil
+	})
+
+	for i := 0; i < total; i++ {
+		_, err := manager.CreateTask(TaskSpec{ID: fmt.Sprintf("flood-%03d", i), Type: "record"})
+		if err != nil {
+			t.Fatalf("CreateTask() error = %v, want nil", err)
+		}
+	}
+
+	waitForStatus(t, manager, fmt.Sprintf("flood-%03d", total-1), model.StatusCompleted)
+
+	tasks, _ := manager.GetAllTasks()
+	for _, task := range tasks {
+		if task.Status != model.StatusCompleted {
+			t.Errorf("Task %s status = %v, want %v", task.ID, task.Status, model.StatusCompleted)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(executed) != total {
+		t.Fatalf("Executed %d tasks, want %d", len(executed), total)
+	}
+
+	for i, id := range executed {
+		if want := fmt.Sprintf("flood-%03d", i); id != want {
+			t.Fatalf("Execution order[%d] = %s, want %s", i, id, want)
+		}
+	}
+}
+
+func TestTaskManager_HighPriorityFirst(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	started := make(chan string, 4)
+	release := make(chan struct{})
+	manager.RegisterExecutor("gated", func(ctx context.Context, task *model.Task) (any, error) {
+		started <- task.ID
+		<-release
+		return "done", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "test-blocker", Type: "gated"})
+	<-started
+
+	manager.CreateTask(TaskSpec{ID: "test-low", Type: "gated", Priority: 1})
+	manager.CreateTask(TaskSpec{ID: "test-high", Type: "gated", Priority: 8})
+	manager.CreateTask(TaskSpec{ID: "test-mid", Type: "gated", Priority: 4})
+
+	close(release)
+
+	for _, want := range []string{"test-high", "test-mid", "test-low"} {
+		if id := <-started; id != want {
+			t.Errorf("Started task = %s, want %s", id, want)
+		}
+	}
+}
+
+func TestTaskManager_CreateTask_InvalidPriority(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 0)
+
+	_, err := manager.CreateTask(TaskSpec{ID: "test-priority", Priority: 10})
+	if err == nil {
+		t.Error("CreateTask() error = nil, want error")
+	}
+}
+
+func TestTaskManager_ScheduledTask(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	runAt := time.Now().Add(100 * time.Millisecond)
+	task, err := manager.CreateTask(TaskSpec{ID: "test-scheduled", RunAt: runAt})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if task.Status != model.StatusScheduled {
+		t.Errorf("CreateTask() Status = %v, want %v", task.Status, model.StatusScheduled)
+	}
+
+	task = waitForStatus(t, manager, "test-scheduled", model.StatusCompleted)
+	if task.StartedAt.Before(runAt) {
+		t.Errorf("Task started at %v, before run_at %v", task.StartedAt, runAt)
+	}
+}
+
+func TestTaskManager_CancelScheduledTask(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	manager.CreateTask(TaskSpec{ID: "test-scheduled", RunAt: time.Now().Add(50 * time.Millisecond)})
+
+	_, err := manager.CancelTask("test-scheduled")
+	if err != nil {
+		t.Fatalf("CancelTask() error = %v, want nil", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	task, _ := manager.GetTask("test-scheduled")
+	if task.Status != model.StatusCancelled {
+		t.Errorf("Task status = %v, want %v", task.Status, model.StatusCancelled)
+	}
+
+	if task.StartedAt != nil {
+		t.Error("Cancelled scheduled task was started")
+	}
+}
+
+func TestTaskManager_SetWorkers(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+	manager.RegisterExecutor("hold", func(ctx context.Context, task *model.Task) (any, error) {
+		started <- struct{}{}
+		<-release
+		return "released", nil
+	})
+
+	if err := manager.SetWorkers(3); err != nil {
+		t.Fatalf("SetWorkers() error = %v, want nil", err)
+	}
+
+	for _, id := range []string{"hold-1", "hold-2", "hold-3"} {
+		manager.CreateTask(TaskSpec{ID: id, Type: "hold"})
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Only %d of 3 tasks started after growing the pool", i)
+		}
+	}
+
+	if err := manager.SetWorkers(1); err != nil {
+		t.Fatalf("SetWorkers() error = %v, want nil", err)
+	}
+
+	// лишние воркеры заняты и должны дождаться окончания своих задач
+	if target, live := manager.GetWorkerCount(); target != 1 || live != 3 {
+		t.Errorf("GetWorkerCount() = %d, %d, want 1, 3", target, live)
+	}
+
+	close(release)
+
+	for _, id := range []string{"hold-1", "hold-2", "hold-3"} {
+		waitForStatus(t, manager, id, model.StatusCompleted)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, live := manager.GetWorkerCount(); live == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, live := manager.GetWorkerCount(); live != 1 {
+		t.Errorf("Live workers = %d after shrink, want 1", live)
+	}
+
+	if err := manager.SetWorkers(-1); err == nil {
+		t.Error("SetWorkers(-1) error = nil, want error")
+	}
+}
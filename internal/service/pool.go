@@ -0,0 +1,234 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultQueue получает задачи, для которых очередь не указана
+const DefaultQueue = "default"
+
+// workerPool - именованная очередь со своими воркерами, чтобы медленные
+// задачи одной очереди не занимали воркеры другой
+type workerPool struct {
+	name    string
+	queue   *taskQueue
+	workers int
+	stops   []chan struct{}
+	live    int
+}
+
+type QueueStats struct {
+	Name        string `json:"name"`
+	Workers     int    `json:"workers"`
+	LiveWorkers int    `json:"live_workers"`
+	Queued      int    `json:"queued"`
+	Paused      bool   `json:"paused"`
+}
+
+func newWorkerPools(opts Options) map[string]*workerPool {
+	pools := map[string]*workerPool{
+		DefaultQueue: {
+			name:    DefaultQueue,
+			queue:   newTaskQueue(opts.PriorityAging),
+			workers: opts.Workers,
+		},
+	}
+
+	for name, workers := range opts.Queues {
+		if pool, exists := pools[name]; exists {
+			pool.workers = workers
+			continue
+		}
+
+		pools[name] = &workerPool{
+			name:    name,
+			queue:   newTaskQueue(opts.PriorityAging),
+			workers: workers,
+		}
+	}
+
+	return pools
+}
+
+// GetWorkerCount возвращает заданный размер пула и число еще работающих воркеров
+// по всем очередям. После уменьшения пула live больше target, пока лишние
+// воркеры дорабатывают задачи
+func (tm *TaskManager) GetWorkerCount() (target, live int) {
+	tm.workerMutex.Lock()
+	defer tm.workerMutex.Unlock()
+
+	for _, pool := range tm.pools {
+		target += pool.workers
+		live += pool.live
+	}
+	return target, live
+}
+
+// SetWorkers меняет число воркеров очереди по умолчанию
+func (tm *TaskManager) SetWorkers(n int) error {
+	return tm.SetQueueWorkers(DefaultQueue, n)
+}
+
+// SetQueueWorkers меняет число воркеров очереди. Новые воркеры запускаются сразу,
+// лишние завершаются после выполнения текущей задачи
+func (tm *TaskManager) SetQueueWorkers(queue string, n int) error {
+	if n < 0 {
+		return validationErrorf("invalid worker count: must not be negative")
+	}
+
+	if tm.isClosing() {
+		return ErrShuttingDown
+	}
+
+	pool, err := tm.lookupPool(queue)
+	if err != nil {
+		return err
+	}
+
+	tm.workerMutex.Lock()
+	defer tm.workerMutex.Unlock()
+
+	previous := pool.workers
+	pool.workers = n
+	tm.resizePool(pool)
+
+	tm.logger.WithFields(logrus.Fields{
+		"queue":    queue,
+		"previous": previous,
+		"workers":  n,
+	}).Info("Worker pool resized")
+	return nil
+}
+
+func (tm *TaskManager) GetQueues() []QueueStats {
+	tm.workerMutex.Lock()
+	defer tm.workerMutex.Unlock()
+
+	stats := make([]QueueStats, 0, len(tm.pools))
+	for _, pool := range tm.pools {
+		stats = append(stats, QueueStats{
+			Name:        pool.name,
+			Workers:     pool.workers,
+			LiveWorkers: pool.live,
+			Queued:      pool.queue.len(),
+			Paused:      pool.queue.isPaused(),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// PauseQueue останавливает выдачу задач очереди воркерам.
+// Уже выполняющиеся задачи доводятся до конца
+func (tm *TaskManager) PauseQueue(queue string) error {
+	pool, err := tm.lookupPool(queue)
+	if err != nil {
+		return err
+	}
+
+	pool.queue.setPaused(true)
+	tm.logger.WithField("queue", queue).Info("Queue paused")
+	return nil
+}
+
+func (tm *TaskManager) ResumeQueue(queue string) error {
+	pool, err := tm.lookupPool(queue)
+	if err != nil {
+		return err
+	}
+
+	pool.queue.setPaused(false)
+	tm.logger.WithField("queue", queue).Info("Queue resumed")
+	return nil
+}
+
+// Pause останавливает выдачу задач воркерам во всех очередях. Задачи продолжают
+// создаваться и сохраняться, а выполняющиеся доводятся до конца
+func (tm *TaskManager) Pause() {
+	tm.setHeld(true)
+	tm.logger.Warn("Task processing paused")
+}
+
+// Resume возобновляет выдачу задач; очереди, приостановленные по отдельности, остаются на паузе
+func (tm *TaskManager) Resume() {
+	tm.setHeld(false)
+	tm.logger.Info("Task processing resumed")
+}
+
+func (tm *TaskManager) IsPaused() bool {
+	return tm.paused.Load()
+}
+
+func (tm *TaskManager) setHeld(held bool) {
+	tm.workerMutex.Lock()
+	defer tm.workerMutex.Unlock()
+
+	tm.paused.Store(held)
+	for _, pool := range tm.pools {
+		pool.queue.setHeld(held)
+	}
+}
+
+func (tm *TaskManager) lookupPool(queue string) (*workerPool, error) {
+	pool, exists := tm.pools[queue]
+	if !exists {
+		return nil, fmt.Errorf("queue %s %w", queue, ErrNotFound)
+	}
+	return pool, nil
+}
+
+func (tm *TaskManager) startWorkers() {
+	tm.workerMutex.Lock()
+	defer tm.workerMutex.Unlock()
+
+	for _, pool := range tm.pools {
+		tm.resizePool(pool)
+	}
+}
+
+// resizePool запускает или останавливает воркеры с конца пула,
+// чтобы их число совпало с workers; вызывается под workerMutex
+func (tm *TaskManager) resizePool(pool *workerPool) {
+	for len(pool.stops) < pool.workers {
+		stop := make(chan struct{})
+		pool.stops = append(pool.stops, stop)
+		pool.live++
+		tm.workerGroup.Add(1)
+		go tm.worker(pool, len(pool.stops), stop)
+	}
+
+	for len(pool.stops) > pool.workers {
+		last := len(pool.stops) - 1
+		close(pool.stops[last])
+		pool.stops = pool.stops[:last]
+	}
+}
+
+func (tm *TaskManager) worker(pool *workerPool, workerID int, stop <-chan struct{}) {
+	logger := tm.logger.WithFields(logrus.Fields{
+		"queue":     pool.name,
+		"worker_id": workerID,
+	})
+	logger.Info("Worker started")
+
+	for {
+		task, ok := pool.queue.pop(stop)
+		if !ok {
+			break
+		}
+		tm.executeTask(task, workerID)
+	}
+
+	tm.workerMutex.Lock()
+	pool.live--
+	tm.workerMutex.Unlock()
+	tm.workerGroup.Done()
+
+	logger.Info("Worker stopped")
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_NamedQueues_Isolation(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers: 1,
+		Queues:  map[string]int{"notifications": 1},
+	})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	manager.RegisterExecutor("report", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "report ready", nil
+	})
+	manager.RegisterExecutor("notify", func(ctx context.Context, task *model.Task) (any, error) {
+		return "sent", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "report-1", Type: "report"})
+	manager.CreateTask(TaskSpec{ID: "report-2", Type: "report"})
+	waitForStatus(t, manager, "report-1", model.StatusRunning)
+
+	task, err := manager.CreateTask(TaskSpec{ID: "notify-1", Type: "notify", Queue: "notifications"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v, want nil", err)
+	}
+
+	if task.Queue != "notifications" {
+		t.Errorf("Queue = %q, want notifications", task.Queue)
+	}
+
+	// уведомление не ждет, пока освободится занятый отчетом воркер очереди default
+	waitForStatus(t, manager, "notify-1", model.StatusCompleted)
+
+	if task, _ := manager.GetTask("report-2"); task.Status != model.StatusPending {
+		t.Errorf("report-2 status = %v, want %v", task.Status, model.StatusPending)
+	}
+}
+
+func TestTaskManager_CreateTask_UnknownQueue(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	_, err := manager.CreateTask(TaskSpec{ID: "lost", Queue: "missing"})
+	if err == nil {
+		t.Fatal("CreateTask() error = nil, want unknown queue error")
+	}
+}
+
+func TestTaskManager_PauseQueue(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers: 1,
+		Queues:  map[string]int{"reports": 1},
+	})
+
+	if err := manager.PauseQueue("reports"); err != nil {
+		t.Fatalf("PauseQueue() error = %v, want nil", err)
+	}
+
+	manager.CreateTask(TaskSpec{ID: "paused", Queue: "reports"})
+	manager.CreateTask(TaskSpec{ID: "unaffected"})
+
+	waitForStatus(t, manager, "unaffected", model.StatusCompleted)
+
+	if task, _ := manager.GetTask("paused"); task.Status != model.StatusPending {
+		t.Errorf("Status = %v while queue paused, want %v", task.Status, model.StatusPending)
+	}
+
+	if err := manager.ResumeQueue("reports"); err != nil {
+		t.Fatalf("ResumeQueue() error = %v, want nil", err)
+	}
+
+	waitForStatus(t, manager, "paused", model.StatusCompleted)
+
+	if err := manager.PauseQueue("missing"); err == nil {
+		t.Error("PauseQueue() error = nil for unknown queue, want error")
+	}
+}
+
+func TestTaskManager_Pause(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers: 1,
+		Queues:  map[string]int{"reports": 1},
+	})
+
+	manager.PauseQueue("reports")
+	manager.Pause()
+
+	if !manager.IsPaused() {
+		t.Fatal("IsPaused() = false after Pause(), want true")
+	}
+
+	if _, err := manager.CreateTask(TaskSpec{ID: "held"}); err != nil {
+		t.Fatalf("CreateTask() error = %v while paused, want nil", err)
+	}
+	manager.CreateTask(TaskSpec{ID: "held-report", Queue: "reports"})
+
+	time.Sleep(150 * time.Millisecond)
+	if task, _ := manager.GetTask("held"); task.Status != model.StatusPending {
+		t.Errorf("Status = %v while paused, want %v", task.Status, model.StatusPending)
+	}
+
+	manager.Resume()
+	waitForStatus(t, manager, "held", model.StatusCompleted)
+
+	// очередь, приостановленная отдельно, остается на паузе после общего возобновления
+	if task, _ := manager.GetTask("held-report"); task.Status != model.StatusPending {
+		t.Errorf("Status = %v in paused queue after Resume(), want %v", task.Status, model.StatusPending)
+	}
+
+	manager.ResumeQueue("reports")
+	waitForStatus(t, manager, "held-report", model.StatusCompleted)
+}
+
+func TestTaskManager_GetQueues(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers: 2,
+		Queues:  map[string]int{"reports": 1},
+	})
+
+	queues := manager.GetQueues()
+	if len(queues) != 2 || queues[0].Name != DefaultQueue || queues[1].Name != "reports" {
+		t.Fatalf("GetQueues() = %+v, want default and reports", queues)
+	}
+
+	if queues[0].Workers != 2 || queues[1].Workers != 1 {
+		t.Errorf("Workers = %d, %d, want 2, 1", queues[0].Workers, queues[1].Workers)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if target, live := manager.GetWorkerCount(); target == 3 && live == 3 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("GetWorkerCount() did not report workers of all queues")
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultProgressInterval - как часто прогресс задачи записывается в хранилище
+const DefaultProgressInterval = time.Second
+
+// ProgressReporter передается исполнителю через контекст и сохраняет прогресс
+// в задаче не чаще одного раза за интервал; промежуточные отчеты заменяются
+// последним, который записывается по истечении интервала или в конце попытки
+type ProgressReporter struct {
+	tm      *TaskManager
+	task    *model.Task
+	lease   *Lease
+	latest  *model.Progress
+	written time.Time
+	timer   *time.Timer
+	closed  bool
+	mutex   sync.Mutex
+}
+
+type progressKey struct{}
+
+// ProgressFromContext возвращает репортер выполняемой задачи. Вне TaskManager
+// возвращается nil, у которого Report ничего не делает
+func ProgressFromContext(ctx context.Context) *ProgressReporter {
+	reporter, _ := ctx.Value(progressKey{}).(*ProgressReporter)
+	return reporter
+}
+
+// Report сообщает процент выполнения (0-100), сообщение и текущий шаг.
+// Отчет о прогрессе заодно продлевает аренду задачи
+func (r *ProgressReporter) Report(percent float64, message, step string) {
+	if r == nil {
+		return
+	}
+	r.lease.Heartbeat()
+
+	now := time.Now()
+	progress := &model.Progress{
+		Percent:   math.Max(0, math.Min(100, percent)),
+		Message:   message,
+		Step:      step,
+		UpdatedAt: now,
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	r.latest = progress
+
+	wait := r.tm.progressInterval - now.Sub(r.written)
+	if wait <= 0 {
+		r.flush()
+		return
+	}
+
+	if r.timer == nil {
+		r.timer = time.AfterFunc(wait, r.flushPending)
+	}
+}
+
+func (r *ProgressReporter) flushPending() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.timer = nil
+	if !r.closed {
+		r.flush()
+	}
+}
+
+// flush записывает последний отчет в задачу; вызывается под r.mutex
+func (r *ProgressReporter) flush() {
+	if r.latest == nil {
+		return
+	}
+
+	progress := r.latest
+	r.latest = nil
+	r.written = time.Now()
+
+	r.tm.mutex.Lock()
+	defer r.tm.mutex.Unlock()
+
+	// отчет, опоздавший к отмене или завершению попытки, не записывается
+	if !r.task.IsRunning() || r.task.Attempt != r.lease.attempt {
+		return
+	}
+
+	r.task.Progress = progress
+	err := r.tm.repo.Update(r.task)
+	if err != nil {
+		r.tm.logger.WithFields(logrus.Fields{
+			"task_id": r.task.ID,
+			"error":   err.Error(),
+		}).Warn("Failed to save task progress")
+	}
+}
+
+// close записывает отложенный отчет и отключает репортер; вызывается без mutex
+func (r *ProgressReporter) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+
+	r.flush()
+	r.closed = true
+}
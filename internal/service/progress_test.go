@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_ProgressThrottled(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:          1,
+		ProgressInterval: 50 * time.Millisecond,
+	})
+
+	reported := make(chan struct{})
+	release := make(chan struct{})
+	manager.RegisterExecutor("import", func(ctx context.Context, task *model.Task) (any, error) {
+		progress := ProgressFromContext(ctx)
+		for i := 1; i <= 10; i++ {
+			progress.Report(float64(i*10), fmt.Sprintf("row %d", i), "load")
+		}
+		close(reported)
+		<-release
+		return "imported", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "import-1", Type: "import"})
+	<-reported
+
+	// первый отчет записан сразу, остальные ждут конца интервала
+	task, _ := manager.GetTask("import-1")
+	if task.Progress == nil || task.Progress.Percent != 10 {
+		t.Fatalf("Progress = %+v, want first report with 10%%", task.Progress)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	task, _ = manager.GetTask("import-1")
+	if task.Progress.Percent != 100 || task.Progress.Message != "row 10" || task.Progress.Step != "load" {
+		t.Errorf("Progress = %+v, want last report", task.Progress)
+	}
+
+	close(release)
+	waitForStatus(t, manager, "import-1", model.StatusCompleted)
+}
+
+func TestProgressReporter_Final(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:          1,
+		ProgressInterval: time.Hour,
+	})
+
+	manager.RegisterExecutor("export", func(ctx context.Context, task *model.Task) (any, error) {
+		progress := ProgressFromContext(ctx)
+		progress.Report(0, "starting", "")
+		progress.Report(250, "done", "upload")
+		return "exported", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "export-1", Type: "export"})
+	task := waitForStatus(t, manager, "export-1", model.StatusCompleted)
+
+	// отложенный отчет записывается при завершении попытки, процент ограничен 100
+	if task.Progress == nil || task.Progress.Percent != 100 || task.Progress.Step != "upload" {
+		t.Errorf("Progress = %+v, want final report with 100%%", task.Progress)
+	}
+
+	// вне TaskManager репортера нет, и Report ничего не делает
+	ProgressFromContext(context.Background()).Report(50, "", "")
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+type queuedTask struct {
+	task       *model.Task
+	enqueuedAt time.Time
+}
+
+// taskQueue - неограниченная очередь ожидающих задач с приоритетами,
+// из которой воркеры забирают задачи по мере освобождения.
+// Внутри одного приоритета порядок FIFO
+type taskQueue struct {
+	levels [model.MaxPriority + 1]*list.List
+	aging  time.Duration
+	notify chan struct{}
+	closed bool
+	paused bool
+	held   bool
+	mutex  sync.Mutex
+}
+
+// newTaskQueue создает очередь; aging - время ожидания, за которое
+// эффективный приоритет задачи растет на единицу (0 отключает старение)
+func newTaskQueue(aging time.Duration) *taskQueue {
+	q := &taskQueue{
+		aging:  aging,
+		notify: make(chan struct{}),
+	}
+
+	for i := range q.levels {
+		q.levels[i] = list.New()
+	}
+
+	return q
+}
+
+func (q *taskQueue) push(task *model.Task) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.levels[clampPriority(task.Priority)].PushBack(&queuedTask{
+		task:       task,
+		enqueuedAt: time.Now(),
+	})
+	q.broadcast()
+}
+
+// pop блокируется, пока в очереди не появится задача, очередь не будет закрыта
+// или не закроется stop; после закрытия stop задачи из очереди не извлекаются
+func (q *taskQueue) pop(stop <-chan struct{}) (*model.Task, bool) {
+	for {
+		select {
+		case <-stop:
+			return nil, false
+		default:
+		}
+
+		q.mutex.Lock()
+		if task := q.next(time.Now()); task != nil {
+			q.mutex.Unlock()
+			return task, true
+		}
+
+		if q.closed {
+			q.mutex.Unlock()
+			return nil, false
+		}
+
+		notify := q.notify
+		q.mutex.Unlock()
+
+		select {
+		case <-notify:
+		case <-stop:
+			return nil, false
+		}
+	}
+}
+
+// next извлекает задачу с наибольшим эффективным приоритетом; вызывается под mutex.
+// Достаточно сравнить только головы уровней: внутри уровня голова ждет дольше всех
+func (q *taskQueue) next(now time.Time) *model.Task {
+	if q.paused || q.held {
+		return nil
+	}
+
+	var best *list.Element
+	var bestLevel, bestScore int
+
+	for level := len(q.levels) - 1; level >= 0; level-- {
+		front := q.levels[level].Front()
+		if front == nil {
+			continue
+		}
+
+		item := front.Value.(*queuedTask)
+		score := level
+		if q.aging > 0 {
+			score += int(now.Sub(item.enqueuedAt) / q.aging)
+		}
+
+		if best == nil || score > bestScore ||
+			(score == bestScore && item.enqueuedAt.Before(best.Value.(*queuedTask).enqueuedAt)) {
+			best, bestLevel, bestScore = front, level, score
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	q.levels[bestLevel].Remove(best)
+	return best.Value.(*queuedTask).task
+}
+
+func (q *taskQueue) len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	total := 0
+	for _, level := range q.levels {
+		total += level.Len()
+	}
+	return total
+}
+
+func (q *taskQueue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.closed = true
+	q.broadcast()
+}
+
+// setPaused приостанавливает выдачу задач воркерам; задачи продолжают
+// поступать в очередь и будут выданы после возобновления
+func (q *taskQueue) setPaused(paused bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.paused = paused
+	if !paused {
+		q.broadcast()
+	}
+}
+
+// setHeld приостанавливает выдачу задач вместе со всеми очередями. Флаг хранится
+// отдельно от paused, чтобы общее возобновление не снимало паузу с отдельной очереди
+func (q *taskQueue) setHeld(held bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.held = held
+	if !held {
+		q.broadcast()
+	}
+}
+
+func (q *taskQueue) isPaused() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.paused
+}
+
+// broadcast будит всех ожидающих воркеров; вызывается под mutex
+func (q *taskQueue) broadcast() {
+	close(q.notify)
+	q.notify = make(chan struct{})
+}
+
+func clampPriority(priority int) int {
+	if priority < model.MinPriority {
+		return model.MinPriority
+	}
+	if priority > model.MaxPriority {
+		return model.MaxPriority
+	}
+	return priority
+}
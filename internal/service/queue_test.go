@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+func TestTaskQueue_FIFO(t *testing.T) {
+	queue := newTaskQueue(0)
+
+	for i := 0; i < 10; i++ {
+		queue.push(model.NewTask(fmt.Sprintf("task-%d", i)))
+	}
+
+	for i := 0; i < 10; i++ {
+		task, ok := queue.pop(nil)
+		if !ok {
+			t.Fatalf("pop() ok = false, want true")
+		}
+
+		want := fmt.Sprintf("task-%d", i)
+		if task.ID != want {
+			t.Errorf("pop() ID = %s, want %s", task.ID, want)
+		}
+	}
+}
+
+func TestTaskQueue_PopBlocksUntilPush(t *testing.T) {
+	queue := newTaskQueue(0)
+
+	result := make(chan *model.Task)
+	go func() {
+		task, _ := queue.pop(nil)
+		result <- task
+	}()
+
+	select {
+	case <-result:
+		t.Fatal("pop() returned before push")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	queue.push(model.NewTask("late-task"))
+
+	select {
+	case task := <-result:
+		if task.ID != "late-task" {
+			t.Errorf("pop() ID = %s, want late-task", task.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pop() did not return after push")
+	}
+}
+
+func TestTaskQueue_Close(t *testing.T) {
+	queue := newTaskQueue(0)
+
+	done := make(chan bool)
+	go func() {
+		_, ok := queue.pop(nil)
+		done <- ok
+	}()
+
+	queue.close()
+
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("pop() ok = true after close, want false")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pop() did not return after close")
+	}
+}
+
+func TestTaskQueue_Stop(t *testing.T) {
+	queue := newTaskQueue(0)
+	stop := make(chan struct{})
+
+	done := make(chan bool)
+	go func() {
+		_, ok := queue.pop(stop)
+		done <- ok
+	}()
+
+	close(stop)
+
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("pop() ok = true after stop, want false")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pop() did not return after stop")
+	}
+
+	queue.push(model.NewTask("left-in-queue"))
+	if _, ok := queue.pop(stop); ok {
+		t.Error("pop() ok = true with stopped worker, want false")
+	}
+
+	if queue.len() != 1 {
+		t.Errorf("len() = %d, want 1", queue.len())
+	}
+}
+
+func TestTaskQueue_PriorityOrder(t *testing.T) {
+	queue := newTaskQueue(0)
+
+	for i, priority := range []int{1, 5, 9, 5, 0} {
+		task := model.NewTask(fmt.Sprintf("task-%d", i))
+		task.Priority = priority
+		queue.push(task)
+	}
+
+	want := []string{"task-2", "task-1", "task-3", "task-0", "task-4"}
+	for _, id := range want {
+		task, _ := queue.pop(nil)
+		if task.ID != id {
+			t.Errorf("pop() ID = %s, want %s", task.ID, id)
+		}
+	}
+}
+
+func TestTaskQueue_Aging(t *testing.T) {
+	queue := newTaskQueue(10 * time.Millisecond)
+
+	low := model.NewTask("low")
+	low.Priority = 0
+	queue.push(low)
+
+	time.Sleep(60 * time.Millisecond)
+
+	high := model.NewTask("high")
+	high.Priority = 3
+	queue.push(high)
+
+	task, _ := queue.pop(nil)
+	if task.ID != "low" {
+		t.Errorf("pop() ID = %s, want aged low priority task first", task.ID)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+const (
+	RateLimitScopeType  = "type"
+	RateLimitScopeQueue = "queue"
+)
+
+// RateLimit ограничивает частоту запуска задач одного типа или одной очереди.
+// Rate - запусков в секунду, Burst - сколько запусков можно сделать подряд
+type RateLimit struct {
+	Scope string
+	Name  string
+	Rate  float64
+	Burst int
+}
+
+type RateLimitState struct {
+	Scope     string  `json:"scope"`
+	Name      string  `json:"name"`
+	Rate      float64 `json:"rate"`
+	Burst     int     `json:"burst"`
+	Tokens    float64 `json:"tokens"`
+	Throttled uint64  `json:"throttled"`
+}
+
+type tokenBucket struct {
+	limit     RateLimit
+	tokens    float64
+	updatedAt time.Time
+	throttled uint64
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.updatedAt = now
+	}
+}
+
+// wait возвращает время до появления целого токена
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+// rateLimiter хранит token bucket для каждого ограниченного типа и очереди
+type rateLimiter struct {
+	buckets map[string]*tokenBucket
+	mutex   sync.Mutex
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (l *rateLimiter) add(limit RateLimit, now time.Time) error {
+	if err := validateRateLimit(&limit); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.buckets[rateLimitKey(limit.Scope, limit.Name)] = &tokenBucket{
+		limit:     limit,
+		tokens:    float64(limit.Burst),
+		updatedAt: now,
+	}
+	return nil
+}
+
+// reserve забирает по токену из всех лимитов задачи или не забирает ни одного.
+// Если токенов не хватает, возвращает время ожидания до следующей попытки
+func (l *rateLimiter) reserve(task *model.Task, now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.buckets) == 0 {
+		return 0
+	}
+
+	var buckets []*tokenBucket
+	for _, key := range []string{rateLimitKey(RateLimitScopeType, task.Type), rateLimitKey(RateLimitScopeQueue, task.Queue)} {
+		if bucket, exists := l.buckets[key]; exists {
+			bucket.refill(now)
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	var wait time.Duration
+	for _, bucket := range buckets {
+		if bucketWait := bucket.wait(); bucketWait > wait {
+			wait = bucketWait
+		}
+	}
+
+	if wait > 0 {
+		for _, bucket := range buckets {
+			if bucket.tokens < 1 {
+				bucket.throttled++
+			}
+		}
+		return wait
+	}
+
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return 0
+}
+
+func (l *rateLimiter) state(now time.Time) []RateLimitState {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	states := make([]RateLimitState, 0, len(l.buckets))
+	for _, bucket := range l.buckets {
+		bucket.refill(now)
+		states = append(states, RateLimitState{
+			Scope:     bucket.limit.Scope,
+			Name:      bucket.limit.Name,
+			Rate:      bucket.limit.Rate,
+			Burst:     bucket.limit.Burst,
+			Tokens:    bucket.tokens,
+			Throttled: bucket.throttled,
+		})
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Scope != states[j].Scope {
+			return states[i].Scope < states[j].Scope
+		}
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+// validateRateLimit проверяет лимит и подставляет Burst, равный округленной вверх частоте
+func validateRateLimit(limit *RateLimit) error {
+	if limit.Scope != RateLimitScopeType && limit.Scope != RateLimitScopeQueue {
+		return validationErrorf("invalid rate limit: scope must be %s or %s", RateLimitScopeType, RateLimitScopeQueue)
+	}
+
+	if limit.Name == "" {
+		return validationErrorf("invalid rate limit: name is required")
+	}
+
+	if limit.Rate <= 0 {
+		return validationErrorf("invalid rate limit: rate must be positive")
+	}
+
+	if limit.Burst < 0 {
+		return validationErrorf("invalid rate limit: burst must not be negative")
+	}
+
+	if limit.Burst == 0 {
+		limit.Burst = int(math.Max(1, math.Ceil(limit.Rate)))
+	}
+
+	return nil
+}
+
+func rateLimitKey(scope, name string) string {
+	return scope + ":" + name
+}
+
+func (tm *TaskManager) GetRateLimits() []RateLimitState {
+	return tm.limiter.state(time.Now())
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	limiter := newRateLimiter()
+	start := time.Now()
+
+	err := limiter.add(RateLimit{Scope: RateLimitScopeType, Name: "email", Rate: 2, Burst: 2}, start)
+	if err != nil {
+		t.Fatalf("add() error = %v, want nil", err)
+	}
+
+	task := &model.Task{Type: "email", Queue: DefaultQueue}
+
+	for i := 0; i < 2; i++ {
+		if wait := limiter.reserve(task, start); wait != 0 {
+			t.Fatalf("reserve() #%d wait = %v, want 0 within burst", i+1, wait)
+		}
+	}
+
+	if wait := limiter.reserve(task, start); wait != 500*time.Millisecond {
+		t.Errorf("reserve() wait = %v, want 500ms", wait)
+	}
+
+	if wait := limiter.reserve(task, start.Add(500*time.Millisecond)); wait != 0 {
+		t.Errorf("reserve() wait = %v after refill, want 0", wait)
+	}
+
+	other := &model.Task{Type: "sms", Queue: DefaultQueue}
+	if wait := limiter.reserve(other, start); wait != 0 {
+		t.Errorf("reserve() wait = %v for unlimited type, want 0", wait)
+	}
+
+	states := limiter.state(start.Add(500 * time.Millisecond))
+	if len(states) != 1 || states[0].Throttled != 1 {
+		t.Errorf("state() = %+v, want one bucket throttled once", states)
+	}
+}
+
+func TestRateLimiter_Reserve_AllOrNothing(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Now()
+
+	limiter.add(RateLimit{Scope: RateLimitScopeType, Name: "report", Rate: 1, Burst: 5}, now)
+	limiter.add(RateLimit{Scope: RateLimitScopeQueue, Name: "reports", Rate: 1, Burst: 1}, now)
+
+	task := &model.Task{Type: "report", Queue: "reports"}
+	limiter.reserve(task, now)
+
+	if wait := limiter.reserve(task, now); wait == 0 {
+		t.Fatal("reserve() wait = 0 with exhausted queue limit, want > 0")
+	}
+
+	// токен типа не должен списываться, если задачу остановил лимит очереди
+	for _, state := range limiter.state(now) {
+		if state.Scope == RateLimitScopeType && state.Tokens != 4 {
+			t.Errorf("Type bucket tokens = %v, want 4", state.Tokens)
+		}
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   RateLimit
+		wantErr bool
+	}{
+		{"valid", RateLimit{Scope: RateLimitScopeQueue, Name: "reports", Rate: 0.5}, false},
+		{"unknown scope", RateLimit{Scope: "host", Name: "api", Rate: 1}, true},
+		{"no name", RateLimit{Scope: RateLimitScopeType, Rate: 1}, true},
+		{"zero rate", RateLimit{Scope: RateLimitScopeType, Name: "email"}, true},
+		{"negative burst", RateLimit{Scope: RateLimitScopeType, Name: "email", Rate: 1, Burst: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRateLimit(&tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRateLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && tt.limit.Burst != 1 {
+				t.Errorf("Burst = %d, want default 1", tt.limit.Burst)
+			}
+		})
+	}
+}
+
+func TestTaskManager_RateLimit_KeepsTasksPending(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:    3,
+		RateLimits: []RateLimit{{Scope: RateLimitScopeType, Name: "call", Rate: 5, Burst: 1}},
+	})
+
+	var mutex sync.Mutex
+	var starts []time.Time
+	manager.RegisterExecutor("call", func(ctx context.Context, task *model.Task) (any, error) {
+		mutex.Lock()
+		starts = append(starts, time.Now())
+		mutex.Unlock()
+		return "called", nil
+	})
+
+	for _, id := range []string{"call-1", "call-2", "call-3"} {
+		manager.CreateTask(TaskSpec{ID: id, Type: "call"})
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	pending := 0
+	for _, id := range []string{"call-1", "call-2", "call-3"} {
+		task, _ := manager.GetTask(id)
+		if task.Status == model.StatusFailed {
+			t.Errorf("Task %s failed, want throttled tasks to stay pending", id)
+		}
+		if task.Status == model.StatusPending {
+			pending++
+		}
+	}
+
+	if pending != 2 {
+		t.Errorf("Pending tasks = %d shortly after start, want 2", pending)
+	}
+
+	for _, id := range []string{"call-1", "call-2", "call-3"} {
+		waitForStatus(t, manager, id, model.StatusCompleted)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if elapsed := starts[2].Sub(starts[0]); elapsed < 350*time.Millisecond {
+		t.Errorf("Three starts took %v, want at least 400ms at 5 per second", elapsed)
+	}
+}
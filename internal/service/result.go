@@ -0,0 +1,40 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// DefaultMaxResultSize - предельный размер результата задачи в байтах
+const DefaultMaxResultSize = 1 << 20
+
+// encodeResult переводит результат исполнителя в JSON и проверяет размер
+// закодированного значения. json.RawMessage сохраняется как есть, nil - как отсутствие результата
+func (tm *TaskManager) encodeResult(result any) (json.RawMessage, string, error) {
+	var encoded json.RawMessage
+	switch value := result.(type) {
+	case nil:
+		return nil, "", nil
+	case json.RawMessage:
+		if len(value) == 0 {
+			return nil, "", nil
+		}
+		if !json.Valid(value) {
+			return nil, "", fmt.Errorf("invalid result: executor returned malformed JSON")
+		}
+		encoded = value
+	default:
+		var err error
+		encoded, err = json.Marshal(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid result: %w", err)
+		}
+	}
+
+	if len(encoded) > tm.maxResultSize {
+		return nil, model.ErrorCodeResultTooLarge, fmt.Errorf("result too large: %d bytes exceeds limit of %d bytes", len(encoded), tm.maxResultSize)
+	}
+	return encoded, "", nil
+}
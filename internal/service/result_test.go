@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_EncodeResult(t *testing.T) {
+	manager := NewTaskManagerForTesting(repository.NewMemoryRepository(), 0)
+
+	tests := []struct {
+		result any
+		want   string
+	}{
+		{json.RawMessage(`{"rows": 10}`), `{"rows": 10}`},
+		{[]int{1, 2}, `[1,2]`},
+		{42, `42`},
+		{"123", `"123"`},
+		{"done", `"done"`},
+		{nil, ``},
+	}
+
+	for _, tt := range tests {
+		got, _, err := manager.encodeResult(tt.result)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("encodeResult(%#v) = %s, %v, want %s", tt.result, got, err, tt.want)
+		}
+	}
+
+	if _, _, err := manager.encodeResult(json.RawMessage(`{"rows":`)); err == nil || !strings.Contains(err.Error(), "invalid result") {
+		t.Errorf("encodeResult(malformed) error = %v, want invalid result", err)
+	}
+}
+
+func TestTaskManager_ResultTooLarge(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:       1,
+		MaxResultSize: 16,
+		RetryPolicy:   model.RetryPolicy{MaxAttempts: 3, InitialBackoff: model.Duration(1)},
+	})
+
+	manager.RegisterExecutor("report", func(ctx context.Context, task *model.Task) (any, error) {
+		return task.Payload, nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "small", Type: "report", Payload: []byte(`{"ok": true}`)})
+	task := waitForStatus(t, manager, "small", model.StatusCompleted)
+	if string(task.Result) != `{"ok": true}` {
+		t.Errorf("Result = %s, want payload as is", task.Result)
+	}
+
+	manager.CreateTask(TaskSpec{ID: "large", Type: "report", Payload: []byte(`{"rows": [1, 2, 3, 4, 5]}`)})
+	task = waitForStatus(t, manager, "large", model.StatusFailed)
+
+	if task.ErrorCode != model.ErrorCodeResultTooLarge || !strings.Contains(task.Error, "result too large") {
+		t.Errorf("Error = %q (%s), want result too large", task.Error, task.ErrorCode)
+	}
+
+	if task.Attempt != 1 || task.Result != nil {
+		t.Errorf("Attempt = %d, Result = %s, want one attempt without result", task.Attempt, task.Result)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+// DefaultRetryPolicy выполняет задачу один раз, без повторов
+var DefaultRetryPolicy = model.RetryPolicy{
+	MaxAttempts:    1,
+	InitialBackoff: model.Duration(time.Second),
+	Multiplier:     2,
+	MaxBackoff:     model.Duration(5 * time.Minute),
+	Jitter:         &defaultJitter,
+}
+
+var defaultJitter = 0.1
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent помечает ошибку исполнителя как неисправимую: задача не будет повторена
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func isRetryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+func validateRetryPolicy(policy model.RetryPolicy) error {
+	if policy.MaxAttempts < 0 {
+		return validationErrorf("invalid retry policy: max_attempts must not be negative")
+	}
+	if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+		return validationErrorf("invalid retry policy: backoff must not be negative")
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		return validationErrorf("invalid retry policy: multiplier must be at least 1")
+	}
+	if policy.Jitter != nil && (*policy.Jitter < 0 || *policy.Jitter > 1) {
+		return validationErrorf("invalid retry policy: jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// mergeRetryPolicy заполняет незаданные поля политики значениями по умолчанию
+func mergeRetryPolicy(policy *model.RetryPolicy, defaults model.RetryPolicy) model.RetryPolicy {
+	if policy == nil {
+		return defaults
+	}
+
+	merged := *policy
+	if merged.MaxAttempts == 0 {
+		merged.MaxAttempts = defaults.MaxAttempts
+	}
+	if merged.InitialBackoff == 0 {
+		merged.InitialBackoff = defaults.InitialBackoff
+	}
+	if merged.Multiplier == 0 {
+		merged.Multiplier = defaults.Multiplier
+	}
+	if merged.MaxBackoff == 0 {
+		merged.MaxBackoff = defaults.MaxBackoff
+	}
+	if merged.Jitter == nil {
+		merged.Jitter = defaults.Jitter
+	}
+	return merged
+}
+
+// retryBackoff возвращает задержку перед повтором после неудачной попытки attempt
+func retryBackoff(policy model.RetryPolicy, attempt int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxBackoff > 0 && backoff > float64(policy.MaxBackoff) {
+		backoff = float64(policy.MaxBackoff)
+	}
+
+	if policy.Jitter != nil && *policy.Jitter > 0 {
+		backoff *= 1 + *policy.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(backoff)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	policy := model.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: model.Duration(time.Second),
+		Multiplier:     2,
+		MaxBackoff:     model.Duration(5 * time.Second),
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := retryBackoff(policy, tt.attempt); got != tt.want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryBackoff_Jitter(t *testing.T) {
+	jitter := 0.5
+	policy := model.RetryPolicy{
+		InitialBackoff: model.Duration(time.Second),
+		Multiplier:     1,
+		Jitter:         &jitter,
+	}
+
+	for i := 0; i < 100; i++ {
+		got := retryBackoff(policy, 1)
+		if got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("retryBackoff() = %v, want within [500ms, 1.5s]", got)
+		}
+	}
+}
+
+func TestMergeRetryPolicy(t *testing.T) {
+	merged := mergeRetryPolicy(&model.RetryPolicy{MaxAttempts: 3}, DefaultRetryPolicy)
+
+	if merged.MaxAttempts != 3 {
+		t.Errorf("MaxAttempts = %d, want 3", merged.MaxAttempts)
+	}
+
+	if merged.InitialBackoff != DefaultRetryPolicy.InitialBackoff {
+		t.Errorf("InitialBackoff = %v, want %v", merged.InitialBackoff, DefaultRetryPolicy.InitialBackoff)
+	}
+
+	if merged.Jitter == nil || *merged.Jitter != *DefaultRetryPolicy.Jitter {
+		t.Errorf("Jitter = %v, want default %v", merged.Jitter, *DefaultRetryPolicy.Jitter)
+	}
+
+	// явный 0 отключает jitter и не заменяется значением по умолчанию
+	noJitter := 0.0
+	merged = mergeRetryPolicy(&model.RetryPolicy{Jitter: &noJitter}, DefaultRetryPolicy)
+
+	if merged.Jitter == nil || *merged.Jitter != 0 {
+		t.Errorf("Jitter = %v, want explicit 0", merged.Jitter)
+	}
+
+	if got := retryBackoff(merged, 1); got != time.Duration(DefaultRetryPolicy.InitialBackoff) {
+		t.Errorf("retryBackoff() without jitter = %v, want %v", got, time.Duration(DefaultRetryPolicy.InitialBackoff))
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if !isRetryable(errors.New("connection reset")) {
+		t.Error("isRetryable() = false for plain error, want true")
+	}
+
+	if isRetryable(Permanent(errors.New("bad input"))) {
+		t.Error("isRetryable() = true for permanent error, want false")
+	}
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ScheduleSpec struct {
+	ID            string
+	Cron          string
+	Timezone      string
+	Template      model.TaskTemplate
+	OverlapPolicy model.OverlapPolicy
+	Paused        bool
+}
+
+func (tm *TaskManager) CreateSchedule(spec ScheduleSpec) (*model.Schedule, error) {
+	tm.logger.WithField("schedule_id", spec.ID).Info("Creating schedule")
+
+	if spec.ID == "" {
+		return nil, validationErrorf("invalid schedule: id is required")
+	}
+
+	cron, err := parseCron(spec.Cron)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Timezone == "" {
+		spec.Timezone = "UTC"
+	}
+
+	location, err := time.LoadLocation(spec.Timezone)
+	if err != nil {
+		return nil, validationErrorf("invalid timezone %q: %w", spec.Timezone, err)
+	}
+
+	switch spec.OverlapPolicy {
+	case "":
+		spec.OverlapPolicy = model.OverlapSkip
+	case model.OverlapSkip, model.OverlapQueue, model.OverlapAllow:
+	default:
+		return nil, validationErrorf("invalid overlap policy %q: must be skip, queue or allow", spec.OverlapPolicy)
+	}
+
+	templateSpec := taskSpecFromTemplate(spec.Template, "")
+	if err := tm.validateSpec(&templateSpec); err != nil {
+		return nil, err
+	}
+	spec.Template.Type = templateSpec.Type
+
+	schedule := &model.Schedule{
+		ID:            spec.ID,
+		Cron:          spec.Cron,
+		Timezone:      spec.Timezone,
+		Template:      spec.Template,
+		OverlapPolicy: spec.OverlapPolicy,
+		Paused:        spec.Paused,
+		CreatedAt:     time.Now(),
+	}
+
+	tm.scheduleMutex.Lock()
+	if !schedule.Paused {
+		tm.setNextRun(schedule, cron, location, schedule.CreatedAt)
+	}
+
+	err = tm.schedules.Create(schedule)
+	tm.scheduleMutex.Unlock()
+
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"schedule_id": spec.ID,
+			"error":       err.Error(),
+		}).Error("Failed to create schedule in repository")
+		return nil, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	tm.wakeSchedules()
+
+	tm.logger.WithFields(logrus.Fields{
+		"schedule_id": schedule.ID,
+		"cron":        schedule.Cron,
+		"timezone":    schedule.Timezone,
+	}).Info("Schedule created successfully")
+	return schedule, nil
+}
+
+func (tm *TaskManager) GetSchedule(id string) (*model.Schedule, error) {
+	schedule, err := tm.schedules.GetByID(id)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"schedule_id": id,
+			"error":       err.Error(),
+		}).Warn("Schedule not found")
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (tm *TaskManager) GetAllSchedules() ([]*model.Schedule, error) {
+	schedules, err := tm.schedules.GetAll()
+	if err != nil {
+		tm.logger.WithField("error", err.Error()).Error("Failed to get all schedules")
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (tm *TaskManager) DeleteSchedule(id string) error {
+	tm.logger.WithField("schedule_id", id).Info("Deleting schedule")
+
+	tm.scheduleMutex.Lock()
+	err := tm.schedules.Delete(id)
+	tm.scheduleMutex.Unlock()
+
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"schedule_id": id,
+			"error":       err.Error(),
+		}).Warn("Cannot delete schedule")
+		return err
+	}
+
+	tm.wakeSchedules()
+
+	tm.logger.WithField("schedule_id", id).Info("Schedule deleted successfully")
+	return nil
+}
+
+func (tm *TaskManager) PauseSchedule(id string) (*model.Schedule, error) {
+	tm.scheduleMutex.Lock()
+	defer tm.scheduleMutex.Unlock()
+
+	schedule, err := tm.schedules.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Paused = true
+	schedule.NextRunAt = nil
+
+	err = tm.schedules.Update(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	tm.logger.WithField("schedule_id", id).Info("Schedule paused")
+	return schedule, nil
+}
+
+func (tm *TaskManager) ResumeSchedule(id string) (*model.Schedule, error) {
+	tm.scheduleMutex.Lock()
+
+	schedule, err := tm.schedules.GetByID(id)
+	if err != nil {
+		tm.scheduleMutex.Unlock()
+		return nil, err
+	}
+
+	cron, location, err := parseSchedule(schedule)
+	if err != nil {
+		tm.scheduleMutex.Unlock()
+		return nil, err
+	}
+
+	schedule.Paused = false
+	tm.setNextRun(schedule, cron, location, time.Now())
+
+	err = tm.schedules.Update(schedule)
+	if err != nil {
+		tm.scheduleMutex.Unlock()
+		return nil, err
+	}
+
+	tm.spawnQueuedRun(schedule)
+	tm.scheduleMutex.Unlock()
+
+	tm.wakeSchedules()
+
+	tm.logger.WithField("schedule_id", id).Info("Schedule resumed")
+	return schedule, nil
+}
+
+// loadSchedules пересчитывает время запуска расписаний, уже сохраненных в репозитории.
+// Пропущенные за время простоя запуски не выполняются
+func (tm *TaskManager) loadSchedules() {
+	schedules, err := tm.schedules.GetAll()
+	if err != nil {
+		tm.logger.WithField("error", err.Error()).Error("Failed to load schedules")
+		return
+	}
+
+	tm.scheduleMutex.Lock()
+	defer tm.scheduleMutex.Unlock()
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		if schedule.Paused {
+			continue
+		}
+
+		cron, location, err := parseSchedule(schedule)
+		if err != nil {
+			tm.logger.WithFields(logrus.Fields{
+				"schedule_id": schedule.ID,
+				"error":       err.Error(),
+			}).Error("Skipping invalid stored schedule")
+			continue
+		}
+
+		tm.setNextRun(schedule, cron, location, now)
+		tm.schedules.Update(schedule)
+	}
+}
+
+func (tm *TaskManager) runSchedules() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		wait := tm.fireDueSchedules(time.Now())
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+		case <-tm.scheduleWake:
+		case <-tm.done:
+			return
+		}
+	}
+}
+
+func (tm *TaskManager) wakeSchedules() {
+	select {
+	case tm.scheduleWake <- struct{}{}:
+	default:
+	}
+}
+
+// fireDueSchedules создает задачи по наступившим расписаниям
+// и возвращает время ожидания до ближайшего следующего запуска
+func (tm *TaskManager) fireDueSchedules(now time.Time) time.Duration {
+	schedules, err := tm.schedules.GetAll()
+	if err != nil {
+		tm.logger.WithField("error", err.Error()).Error("Failed to get schedules")
+		return time.Minute
+	}
+
+	tm.scheduleMutex.Lock()
+	defer tm.scheduleMutex.Unlock()
+
+	wait := time.Hour
+	for _, schedule := range schedules {
+		if schedule.Paused || schedule.NextRunAt == nil {
+			continue
+		}
+
+		if !schedule.NextRunAt.After(now) {
+			cron, location, err := parseSchedule(schedule)
+			if err != nil {
+				continue
+			}
+
+			tm.fireSchedule(schedule, *schedule.NextRunAt)
+			tm.setNextRun(schedule, cron, location, now)
+			tm.schedules.Update(schedule)
+
+			if schedule.NextRunAt == nil {
+				continue
+			}
+		}
+
+		if until := schedule.NextRunAt.Sub(now); until < wait {
+			wait = until
+		}
+	}
+
+	return wait
+}
+
+// maxQueuedRuns - сколько запусков политика queue держит в ожидании
+const maxQueuedRuns = 1
+
+// fireSchedule применяет политику перекрытия; вызывается под scheduleMutex
+func (tm *TaskManager) fireSchedule(schedule *model.Schedule, fireTime time.Time) {
+	logger := tm.logger.WithFields(logrus.Fields{
+		"schedule_id": schedule.ID,
+		"fire_time":   fireTime.Format(time.RFC3339),
+	})
+
+	firedAt := fireTime
+	schedule.LastRunAt = &firedAt
+
+	if schedule.OverlapPolicy != model.OverlapAllow && tm.hasActiveRun(schedule) {
+		// очередь ограничена, чтобы зависшая задача не копила запуски,
+		// которые потом выполнятся подряд
+		if schedule.OverlapPolicy == model.OverlapQueue && len(schedule.QueuedRuns) < maxQueuedRuns {
+			schedule.QueuedRuns = append(schedule.QueuedRuns, fireTime)
+			logger.WithField("previous_task_id", schedule.LastTaskID).Info("Previous run still active, schedule run queued")
+		} else {
+			logger.WithField("previous_task_id", schedule.LastTaskID).Info("Previous run still active, schedule run skipped")
+		}
+		return
+	}
+
+	tm.spawnScheduledTask(schedule, fireTime)
+}
+
+func (tm *TaskManager) hasActiveRun(schedule *model.Schedule) bool {
+	if schedule.LastTaskID == "" {
+		return false
+	}
+
+	task, err := tm.repo.GetByID(schedule.LastTaskID)
+	if err != nil {
+		return false
+	}
+
+	return !task.IsCompleted()
+}
+
+func (tm *TaskManager) spawnScheduledTask(schedule *model.Schedule, fireTime time.Time) {
+	spec := taskSpecFromTemplate(schedule.Template, scheduledTaskID(schedule.ID, fireTime))
+	spec.ScheduleID = schedule.ID
+
+	task, err := tm.createTask(spec)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"schedule_id": schedule.ID,
+			"task_id":     spec.ID,
+			"error":       err.Error(),
+		}).Error("Failed to create task from schedule")
+		return
+	}
+
+	schedule.LastTaskID = task.ID
+}
+
+// spawnQueuedRun запускает отложенный политикой queue запуск,
+// если предыдущая задача завершилась; вызывается под scheduleMutex
+func (tm *TaskManager) spawnQueuedRun(schedule *model.Schedule) {
+	if schedule.Paused || len(schedule.QueuedRuns) == 0 || tm.hasActiveRun(schedule) {
+		return
+	}
+
+	fireTime := schedule.QueuedRuns[0]
+	schedule.QueuedRuns = schedule.QueuedRuns[1:]
+	tm.spawnScheduledTask(schedule, fireTime)
+	tm.schedules.Update(schedule)
+}
+
+func (tm *TaskManager) scheduledTaskFinished(task *model.Task) {
+	tm.scheduleMutex.Lock()
+	defer tm.scheduleMutex.Unlock()
+
+	schedule, err := tm.schedules.GetByID(task.ScheduleID)
+	if err != nil || schedule.LastTaskID != task.ID {
+		return
+	}
+
+	tm.spawnQueuedRun(schedule)
+}
+
+// setNextRun вычисляет следующий запуск после after в часовом поясе расписания
+func (tm *TaskManager) setNextRun(schedule *model.Schedule, cron *cronExpression, location *time.Location, after time.Time) {
+	next := cron.next(after.In(location))
+	if next.IsZero() {
+		schedule.NextRunAt = nil
+		tm.logger.WithField("schedule_id", schedule.ID).Warn("Schedule has no upcoming runs")
+		return
+	}
+
+	schedule.NextRunAt = &next
+}
+
+func parseSchedule(schedule *model.Schedule) (*cronExpression, *time.Location, error) {
+	cron, err := parseCron(schedule.Cron)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, nil, validationErrorf("invalid timezone %q: %w", schedule.Timezone, err)
+	}
+
+	return cron, location, nil
+}
+
+func taskSpecFromTemplate(template model.TaskTemplate, id string) TaskSpec {
+	return TaskSpec{
+		ID:               id,
+		Type:             template.Type,
+		Payload:          template.Payload,
+		Queue:            template.Queue,
+		Priority:         template.Priority,
+		ConcurrencyKey:   template.ConcurrencyKey,
+		ConcurrencyLimit: template.ConcurrencyLimit,
+		Timeout:          template.Timeout.Duration(),
+		RetryPolicy:      template.RetryPolicy,
+	}
+}
+
+// scheduledTaskID строит ID задачи из ID расписания и времени срабатывания
+func scheduledTaskID(scheduleID string, fireTime time.Time) string {
+	return fmt.Sprintf("%s-%s", scheduleID, fireTime.UTC().Format("20060102T1504Z"))
+}
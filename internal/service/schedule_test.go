@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func setupScheduleManager(t *testing.T, policy model.OverlapPolicy) (*TaskManager, chan struct{}) {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 2)
+
+	release := make(chan struct{})
+	manager.RegisterExecutor("report", func(ctx context.Context, task *model.Task) (any, error) {
+		<-release
+		return "report ready", nil
+	})
+
+	_, err := manager.CreateSchedule(ScheduleSpec{
+		ID:            "nightly",
+		Cron:          "0 2 * * *",
+		Timezone:      "UTC",
+		Template:      model.TaskTemplate{Type: "report"},
+		OverlapPolicy: policy,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v, want nil", err)
+	}
+
+	return manager, release
+}
+
+// fireAt обрабатывает расписание так, будто наступило время fireTime. Тесты используют
+// даты в будущем, чтобы фоновый цикл расписаний не считал их уже наступившими
+func fireAt(manager *TaskManager, id string, fireTime time.Time) {
+	manager.scheduleMutex.Lock()
+	schedule, _ := manager.schedules.GetByID(id)
+	schedule.NextRunAt = &fireTime
+	manager.scheduleMutex.Unlock()
+
+	manager.fireDueSchedules(fireTime)
+}
+
+func TestTaskManager_CreateSchedule(t *testing.T) {
+	manager, release := setupScheduleManager(t, "")
+	defer close(release)
+
+	schedule, err := manager.GetSchedule("nightly")
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v, want nil", err)
+	}
+
+	if schedule.OverlapPolicy != model.OverlapSkip {
+		t.Errorf("OverlapPolicy = %v, want %v", schedule.OverlapPolicy, model.OverlapSkip)
+	}
+
+	if schedule.NextRunAt == nil || schedule.NextRunAt.UTC().Hour() != 2 {
+		t.Errorf("NextRunAt = %v, want next 02:00 UTC", schedule.NextRunAt)
+	}
+}
+
+func TestTaskManager_CreateSchedule_Invalid(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 0)
+
+	specs := []ScheduleSpec{
+		{ID: "bad-cron", Cron: "every day"},
+		{ID: "bad-tz", Cron: "0 2 * * *", Timezone: "Mars/Olympus"},
+		{ID: "bad-policy", Cron: "0 2 * * *", OverlapPolicy: "sometimes"},
+		{ID: "bad-type", Cron: "0 2 * * *", Template: model.TaskTemplate{Type: "missing"}},
+	}
+
+	for _, spec := range specs {
+		if _, err := manager.CreateSchedule(spec); err == nil {
+			t.Errorf("CreateSchedule(%s) error = nil, want error", spec.ID)
+		}
+	}
+}
+
+func TestTaskManager_ScheduleSpawnsTask(t *testing.T) {
+	manager, release := setupScheduleManager(t, model.OverlapSkip)
+	close(release)
+
+	fireTime := time.Date(2099, 7, 3, 2, 0, 0, 0, time.UTC)
+	fireAt(manager, "nightly", fireTime)
+
+	taskID := scheduledTaskID("nightly", fireTime)
+	task := waitForStatus(t, manager, taskID, model.StatusCompleted)
+
+	if task.ScheduleID != "nightly" {
+		t.Errorf("Task ScheduleID = %q, want nightly", task.ScheduleID)
+	}
+
+	schedule, _ := manager.GetSchedule("nightly")
+	if schedule.LastTaskID != taskID {
+		t.Errorf("LastTaskID = %q, want %q", schedule.LastTaskID, taskID)
+	}
+
+	if schedule.NextRunAt == nil || !schedule.NextRunAt.After(fireTime) {
+		t.Errorf("NextRunAt = %v, want after %v", schedule.NextRunAt, fireTime)
+	}
+}
+
+func TestTaskManager_ScheduleOverlapSkip(t *testing.T) {
+	manager, release := setupScheduleManager(t, model.OverlapSkip)
+	defer close(release)
+
+	first := time.Date(2099, 7, 3, 2, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	fireAt(manager, "nightly", first)
+	waitForStatus(t, manager, scheduledTaskID("nightly", first), model.StatusRunning)
+
+	fireAt(manager, "nightly", second)
+
+	if _, err := manager.GetTask(scheduledTaskID("nightly", second)); err == nil {
+		t.Error("Overlapping run was created with skip policy")
+	}
+}
+
+func TestTaskManager_ScheduleOverlapQueue(t *testing.T) {
+	manager, release := setupScheduleManager(t, model.OverlapQueue)
+
+	first := time.Date(2099, 7, 3, 2, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	fireAt(manager, "nightly", first)
+	waitForStatus(t, manager, scheduledTaskID("nightly", first), model.StatusRunning)
+
+	fireAt(manager, "nightly", second)
+
+	if _, err := manager.GetTask(scheduledTaskID("nightly", second)); err == nil {
+		t.Error("Queued run was created before previous run finished")
+	}
+
+	close(release)
+
+	waitForStatus(t, manager, scheduledTaskID("nightly", second), model.StatusCompleted)
+}
+
+func TestTaskManager_ScheduleOverlapQueueLimit(t *testing.T) {
+	manager, release := setupScheduleManager(t, model.OverlapQueue)
+
+	first := time.Date(2099, 7, 3, 2, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	third := second.Add(24 * time.Hour)
+
+	fireAt(manager, "nightly", first)
+	waitForStatus(t, manager, scheduledTaskID("nightly", first), model.StatusRunning)
+
+	fireAt(manager, "nightly", second)
+	fireAt(manager, "nightly", third)
+
+	manager.scheduleMutex.Lock()
+	schedule, _ := manager.schedules.GetByID("nightly")
+	queued := len(schedule.QueuedRuns)
+	manager.scheduleMutex.Unlock()
+
+	if queued != 1 {
+		t.Errorf("QueuedRuns = %d, want 1", queued)
+	}
+
+	close(release)
+	waitForStatus(t, manager, scheduledTaskID("nightly", second), model.StatusCompleted)
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := manager.GetTask(scheduledTaskID("nightly", third)); err == nil {
+		t.Error("Run beyond the queue limit was created")
+	}
+}
+
+func TestTaskManager_ScheduleOverlapAllow(t *testing.T) {
+	manager, release := setupScheduleManager(t, model.OverlapAllow)
+	defer close(release)
+
+	first := time.Date(2099, 7, 3, 2, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	fireAt(manager, "nightly", first)
+	fireAt(manager, "nightly", second)
+
+	waitForStatus(t, manager, scheduledTaskID("nightly", first), model.StatusRunning)
+	waitForStatus(t, manager, scheduledTaskID("nightly", second), model.StatusRunning)
+}
+
+func TestTaskManager_PauseResumeSchedule(t *testing.T) {
+	manager, release := setupScheduleManager(t, model.OverlapSkip)
+	defer close(release)
+
+	schedule, err := manager.PauseSchedule("nightly")
+	if err != nil {
+		t.Fatalf("PauseSchedule() error = %v, want nil", err)
+	}
+
+	if !schedule.Paused || schedule.NextRunAt != nil {
+		t.Errorf("Paused schedule = %+v, want paused without next run", schedule)
+	}
+
+	manager.fireDueSchedules(time.Now().Add(48 * time.Hour))
+
+	if schedule.LastTaskID != "" {
+		t.Error("Paused schedule spawned a task")
+	}
+
+	schedule, err = manager.ResumeSchedule("nightly")
+	if err != nil {
+		t.Fatalf("ResumeSchedule() error = %v, want nil", err)
+	}
+
+	if schedule.Paused || schedule.NextRunAt == nil {
+		t.Errorf("Resumed schedule = %+v, want active with next run", schedule)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+type scheduledTask struct {
+	at   time.Time
+	seq  uint64
+	task *model.Task
+}
+
+type scheduleHeap []*scheduledTask
+
+func (h scheduleHeap) Len() int { return len(h) }
+
+func (h scheduleHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h scheduleHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *scheduleHeap) Push(x interface{}) { *h = append(*h, x.(*scheduledTask)) }
+
+func (h *scheduleHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// scheduler хранит отложенные задачи в min-heap по времени запуска
+// и обслуживает их одной горутиной с единственным таймером
+type scheduler struct {
+	items  scheduleHeap
+	seq    uint64
+	fire   func(task *model.Task)
+	wake   chan struct{}
+	done   chan struct{}
+	closed bool
+	mutex  sync.Mutex
+}
+
+func newScheduler(fire func(task *model.Task)) *scheduler {
+	return &scheduler{
+		fire: fire,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+func (s *scheduler) add(at time.Time, task *model.Task) {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return
+	}
+
+	s.seq++
+	heap.Push(&s.items, &scheduledTask{at: at, seq: s.seq, task: task})
+	s.mutex.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *scheduler) len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.items.Len()
+}
+
+func (s *scheduler) run() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		due, wait := s.takeDue(time.Now())
+		for _, task := range due {
+			s.fire(task)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// takeDue извлекает все наступившие задачи и возвращает время до следующей
+func (s *scheduler) takeDue(now time.Time) ([]*model.Task, time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var due []*model.Task
+	for s.items.Len() > 0 && !s.items[0].at.After(now) {
+		due = append(due, heap.Pop(&s.items).(*scheduledTask).task)
+	}
+
+	wait := time.Hour
+	if s.items.Len() > 0 {
+		wait = s.items[0].at.Sub(now)
+	}
+
+	return due, wait
+}
+
+func (s *scheduler) stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+	close(s.done)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+func TestScheduler_FiresInOrder(t *testing.T) {
+	fired := make(chan string, 3)
+	s := newScheduler(func(task *model.Task) {
+		fired <- task.ID
+	})
+	go s.run()
+	defer s.stop()
+
+	now := time.Now()
+	s.add(now.Add(60*time.Millisecond), model.NewTask("third"))
+	s.add(now.Add(20*time.Millisecond), model.NewTask("first"))
+	s.add(now.Add(40*time.Millisecond), model.NewTask("second"))
+
+	for _, want := range []string{"first", "second", "third"} {
+		select {
+		case id := <-fired:
+			if id != want {
+				t.Errorf("Fired task = %s, want %s", id, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Task %s was not fired", want)
+		}
+	}
+}
+
+func TestScheduler_ManyFutureTasks(t *testing.T) {
+	var count int
+	var mutex sync.Mutex
+	s := newScheduler(func(task *model.Task) {
+		mutex.Lock()
+		count++
+		mutex.Unlock()
+	})
+	go s.run()
+	defer s.stop()
+
+	goroutines := runtime.NumGoroutine()
+
+	future := time.Now().Add(time.Hour)
+	for i := 0; i < 10000; i++ {
+		s.add(future.Add(time.Duration(i)*time.Second), model.NewTask(fmt.Sprintf("future-%d", i)))
+	}
+	s.add(time.Now(), model.NewTask("due-now"))
+
+	if diff := runtime.NumGoroutine() - goroutines; diff > 0 {
+		t.Errorf("Scheduler started %d extra goroutines for future tasks", diff)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if count != 1 {
+		t.Errorf("Fired %d tasks, want 1", count)
+	}
+
+	if s.len() != 10000 {
+		t.Errorf("len() = %d, want 10000", s.len())
+	}
+}
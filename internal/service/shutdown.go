@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// ShutdownPolicy определяет, что происходит с задачами, не успевшими
+// завершиться к дедлайну Shutdown
+type ShutdownPolicy string
+
+const (
+	// ShutdownRequeue возвращает задачу в pending, чтобы ее можно было продолжить после перезапуска
+	ShutdownRequeue ShutdownPolicy = "requeue"
+	// ShutdownFail переводит задачу в failed с ошибкой "interrupted by shutdown"
+	ShutdownFail ShutdownPolicy = "fail"
+)
+
+// Shutdown перестает принимать задачи и ждет, пока воркеры доделают текущие,
+// но не дольше дедлайна ctx. Задачи, не завершившиеся к дедлайну, прерываются
+// согласно политике, а Shutdown возвращает ошибку контекста
+func (tm *TaskManager) Shutdown(ctx context.Context) error {
+	if !tm.closing.CompareAndSwap(false, true) {
+		return fmt.Errorf("task manager is already shut down")
+	}
+
+	tm.logger.Info("Shutting down task manager")
+
+	close(tm.done)
+	tm.scheduler.stop()
+
+	// воркеры доделывают текущую задачу и выходят, новые задачи остаются pending
+	tm.workerMutex.Lock()
+	for _, pool := range tm.pools {
+		pool.workers = 0
+		tm.resizePool(pool)
+		pool.queue.close()
+	}
+	tm.workerMutex.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		tm.workerGroup.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		tm.logger.Info("Task manager stopped, all running tasks finished")
+		return nil
+	case <-ctx.Done():
+	}
+
+	interrupted := tm.interruptRunning()
+	tm.logger.WithFields(logrus.Fields{
+		"interrupted": interrupted,
+		"policy":      tm.shutdownPolicy,
+	}).Warn("Shutdown deadline reached, running tasks interrupted")
+	return ctx.Err()
+}
+
+// interruptRunning снимает с воркеров все выполняющиеся задачи и возвращает их число
+func (tm *TaskManager) interruptRunning() int {
+	var failed []*model.Task
+	count := 0
+
+	tm.mutex.Lock()
+	now := time.Now()
+	interruptErr := fmt.Errorf("interrupted by shutdown")
+
+	for id := range tm.cancels {
+		task, err := tm.repo.GetByID(id)
+		if err != nil || !task.IsRunning() {
+			continue
+		}
+
+		logger := tm.logger.WithFields(logrus.Fields{
+			"task_id": task.ID,
+			"attempt": task.Attempt,
+		})
+
+		count++
+		tm.abandonAttempt(task, interruptErr, now)
+		task.ErrorCode = model.ErrorCodeInterrupted
+
+		if tm.shutdownPolicy == ShutdownRequeue {
+			task.Status = model.StatusPending
+			task.Error = interruptErr.Error()
+			tm.repo.Update(task)
+			tm.logEvent(task, model.LogLevelWarn, "attempt %d interrupted by shutdown, task left pending", task.Attempt)
+			logger.Warn("Task interrupted by shutdown, left pending")
+			continue
+		}
+
+		tm.failTask(task, interruptErr, logger)
+		failed = append(failed, task)
+	}
+	tm.mutex.Unlock()
+
+	for _, task := range failed {
+		tm.taskFinished(task)
+	}
+
+	return count
+}
+
+func (tm *TaskManager) isClosing() bool {
+	return tm.closing.Load()
+}
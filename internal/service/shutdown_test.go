@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskManager_ShutdownDrains(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	started := make(chan struct{}, 2)
+	manager.RegisterExecutor("short", func(ctx context.Context, task *model.Task) (any, error) {
+		started <- struct{}{}
+		time.Sleep(50 * time.Millisecond)
+		return "done", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "short-1", Type: "short"})
+	manager.CreateTask(TaskSpec{ID: "short-2", Type: "short"})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v, want nil", err)
+	}
+
+	// выполнявшаяся задача доработала, ожидавшая осталась в очереди
+	first, _ := manager.GetTask("short-1")
+	second, _ := manager.GetTask("short-2")
+	if first.Status != model.StatusCompleted || second.Status != model.StatusPending {
+		t.Errorf("Statuses = %s, %s, want completed, pending", first.Status, second.Status)
+	}
+
+	if _, err := manager.CreateTask(TaskSpec{ID: "short-3", Type: "short"}); err == nil {
+		t.Error("CreateTask() after Shutdown() error = nil, want shutting down")
+	}
+
+	if err := manager.Shutdown(ctx); err == nil {
+		t.Error("Second Shutdown() error = nil, want error")
+	}
+}
+
+func TestTaskManager_ShutdownInterrupts(t *testing.T) {
+	tests := []struct {
+		policy ShutdownPolicy
+		want   model.TaskStatus
+	}{
+		{ShutdownRequeue, model.StatusPending},
+		{ShutdownFail, model.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			repo := repository.NewMemoryRepository()
+			manager := NewTaskManagerForTestingWithOptions(repo, Options{Workers: 1, ShutdownPolicy: tt.policy})
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			manager.RegisterExecutor("stuck", func(ctx context.Context, task *model.Task) (any, error) {
+				close(started)
+				<-release
+				return "late", nil
+			})
+
+			manager.CreateTask(TaskSpec{ID: "stuck-1", Type: "stuck"})
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := manager.Shutdown(ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Shutdown() error = %v, want deadline exceeded", err)
+			}
+
+			task, _ := manager.GetTask("stuck-1")
+			if task.Status != tt.want || task.Error != "interrupted by shutdown" || task.ErrorCode != model.ErrorCodeInterrupted {
+				t.Errorf("Task = %s (%q, %s), want %s interrupted by shutdown", task.Status, task.Error, task.ErrorCode, tt.want)
+			}
+
+			// результат, пришедший после дедлайна, отбрасывается
+			close(release)
+			time.Sleep(20 * time.Millisecond)
+
+			task, _ = manager.GetTask("stuck-1")
+			if task.Status != tt.want || task.Result != nil {
+				t.Errorf("Task after late result = %s with %s, want %s without result", task.Status, task.Result, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskManager_ShutdownKeepsWorkflowSteps(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	manager.RegisterExecutor("step", func(ctx context.Context, task *model.Task) (any, error) {
+		if task.WorkflowStep == "first" {
+			close(started)
+			<-release
+		}
+		return "done", nil
+	})
+
+	_, err := manager.CreateWorkflow(WorkflowSpec{
+		ID: "drain",
+		Steps: []model.WorkflowStep{
+			{Name: "first", Type: "step"},
+			{Name: "second", Type: "step"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v, want nil", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- manager.Shutdown(ctx)
+	}()
+
+	// первый шаг доделывается уже после начала остановки
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown() error = %v, want nil", err)
+	}
+
+	// следующий шаг создан, но не запущен, и workflow не падает
+	workflow, _ := manager.GetWorkflow("drain")
+	if workflow.Status != model.WorkflowRunning {
+		t.Errorf("Workflow status = %s (%s), want %s", workflow.Status, workflow.Error, model.WorkflowRunning)
+	}
+
+	second, err := manager.GetTask(workflow.StepStates[1].TaskID)
+	if err != nil || second.Status != model.StatusPending {
+		t.Errorf("Second step = %+v, %v, want pending task", second, err)
+	}
+
+	if _, err := manager.CreateWorkflow(WorkflowSpec{
+		ID:    "late",
+		Steps: []model.WorkflowStep{{Name: "only", Type: "step"}},
+	}); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("CreateWorkflow() after Shutdown() error = %v, want %v", err, ErrShuttingDown)
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+)
+
+const (
+	// DefaultTaskLogSize - сколько последних записей хранит журнал задачи
+	DefaultTaskLogSize = 1000
+	maxLogMessage      = 4096
+)
+
+// taskLog - кольцевой буфер записей журнала одной задачи
+type taskLog struct {
+	entries []model.LogEntry
+	start   int
+	count   int
+	next    int64
+	updated chan struct{}
+	mutex   sync.Mutex
+}
+
+func newTaskLog(size int) *taskLog {
+	return &taskLog{
+		entries: make([]model.LogEntry, size),
+		updated: make(chan struct{}),
+	}
+}
+
+func (l *taskLog) append(level, source, message string) {
+	if len(message) > maxLogMessage {
+		message = message[:maxLogMessage] + "... (truncated)"
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry := model.LogEntry{
+		Offset:  l.next,
+		Time:    time.Now(),
+		Level:   level,
+		Source:  source,
+		Message: message,
+	}
+
+	if l.count < len(l.entries) {
+		l.entries[(l.start+l.count)%len(l.entries)] = entry
+		l.count++
+	} else {
+		l.entries[l.start] = entry
+		l.start = (l.start + 1) % len(l.entries)
+	}
+	l.next++
+
+	// ожидающие читатели просыпаются, канал заменяется новым
+	close(l.updated)
+	l.updated = make(chan struct{})
+}
+
+func (l *taskLog) read(query TaskLogQuery) *TaskLogPage {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	first := l.next - int64(l.count)
+	offset := query.Offset
+	if query.Tail > 0 {
+		offset = max(first, l.next-int64(query.Tail))
+	}
+
+	page := &TaskLogPage{
+		FirstOffset: first,
+		updated:     l.updated,
+	}
+
+	if offset < first {
+		page.Dropped = first - offset
+		offset = first
+	}
+	offset = min(offset, l.next)
+
+	n := l.next - offset
+	if query.Limit > 0 {
+		n = min(n, int64(query.Limit))
+	}
+
+	page.Entries = make([]model.LogEntry, 0, n)
+	for i := int64(0); i < n; i++ {
+		index := (l.start + int(offset-first+i)) % len(l.entries)
+		page.Entries = append(page.Entries, l.entries[index])
+	}
+	page.NextOffset = offset + n
+	page.More = page.NextOffset < l.next
+	return page
+}
+
+func (l *taskLog) close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	close(l.updated)
+	l.updated = make(chan struct{})
+}
+
+// TaskLogQuery выбирает записи журнала начиная с Offset, но не больше Limit.
+// Tail > 0 означает последние Tail записей и заменяет Offset
+type TaskLogQuery struct {
+	Offset int64
+	Limit  int
+	Tail   int
+}
+
+type TaskLogPage struct {
+	Entries     []model.LogEntry `json:"entries"`
+	FirstOffset int64            `json:"first_offset"`
+	NextOffset  int64            `json:"next_offset"`
+	// Dropped - сколько запрошенных записей уже вытеснено из буфера
+	Dropped int64 `json:"dropped,omitempty"`
+	More    bool  `json:"more"`
+	// Finished означает, что задача в конечном статусе и новых записей не будет
+	Finished bool `json:"finished"`
+	updated  <-chan struct{}
+}
+
+// Updated закрывается при появлении новых записей после чтения страницы
+// или при удалении задачи
+func (p *TaskLogPage) Updated() <-chan struct{} {
+	return p.updated
+}
+
+// TaskLogger пишет в журнал задачи от имени исполнителя. Реализует io.Writer:
+// каждая строка становится отдельной записью уровня info
+type TaskLogger struct {
+	log *taskLog
+}
+
+type taskLoggerKey struct{}
+
+// TaskLoggerFromContext возвращает журнал выполняемой задачи. Вне TaskManager
+// возвращается nil, запись в который ничего не делает
+func TaskLoggerFromContext(ctx context.Context) *TaskLogger {
+	logger, _ := ctx.Value(taskLoggerKey{}).(*TaskLogger)
+	return logger
+}
+
+func (l *TaskLogger) Infof(format string, args ...any) {
+	l.logf(model.LogLevelInfo, format, args...)
+}
+
+func (l *TaskLogger) Warnf(format string, args ...any) {
+	l.logf(model.LogLevelWarn, format, args...)
+}
+
+func (l *TaskLogger) Errorf(format string, args ...any) {
+	l.logf(model.LogLevelError, format, args...)
+}
+
+func (l *TaskLogger) Write(p []byte) (int, error) {
+	if l != nil {
+		for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+			l.log.append(model.LogLevelInfo, model.LogSourceExecutor, line)
+		}
+	}
+	return len(p), nil
+}
+
+func (l *TaskLogger) logf(level, format string, args ...any) {
+	if l == nil {
+		return
+	}
+	l.log.append(level, model.LogSourceExecutor, fmt.Sprintf(format, args...))
+}
+
+// taskLog возвращает журнал задачи, создавая его при первой записи
+func (tm *TaskManager) taskLog(id string) *taskLog {
+	tm.logMutex.Lock()
+	defer tm.logMutex.Unlock()
+
+	log, exists := tm.logs[id]
+	if !exists {
+		log = newTaskLog(tm.taskLogSize)
+		tm.logs[id] = log
+	}
+	return log
+}
+
+// logEvent записывает в журнал задачи событие ее жизненного цикла
+func (tm *TaskManager) logEvent(task *model.Task, level, format string, args ...any) {
+	tm.taskLog(task.ID).append(level, model.LogSourceSystem, fmt.Sprintf(format, args...))
+}
+
+func (tm *TaskManager) dropTaskLog(id string) {
+	tm.logMutex.Lock()
+	log, exists := tm.logs[id]
+	delete(tm.logs, id)
+	tm.logMutex.Unlock()
+
+	if exists {
+		log.close()
+	}
+}
+
+func (tm *TaskManager) GetTaskLogs(id string, query TaskLogQuery) (*TaskLogPage, error) {
+	task, err := tm.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTaskNotFound, err)
+	}
+
+	if query.Offset < 0 || query.Limit < 0 || query.Tail < 0 {
+		return nil, validationErrorf("invalid log query: offset, limit and tail must not be negative")
+	}
+
+	// статус читается до записей: конечная запись появляется вместе
+	// со сменой статуса под mutex, поэтому она не будет пропущена
+	tm.mutex.Lock()
+	finished := task.IsCompleted()
+	tm.mutex.Unlock()
+
+	page := tm.taskLog(id).read(query)
+	page.Finished = finished
+	return page, nil
+}
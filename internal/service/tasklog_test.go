@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func TestTaskLog_Bounded(t *testing.T) {
+	log := newTaskLog(3)
+	for i := 0; i < 5; i++ {
+		log.append(model.LogLevelInfo, model.LogSourceExecutor, fmt.Sprintf("line %d", i))
+	}
+
+	page := log.read(TaskLogQuery{})
+	if page.FirstOffset != 2 || page.NextOffset != 5 || len(page.Entries) != 3 {
+		t.Fatalf("read() = first %d, next %d, %d entries, want 2, 5, 3", page.FirstOffset, page.NextOffset, len(page.Entries))
+	}
+
+	if page.Dropped != 2 || page.Entries[0].Message != "line 2" {
+		t.Errorf("read() dropped %d, first %q, want 2 and line 2", page.Dropped, page.Entries[0].Message)
+	}
+
+	page = log.read(TaskLogQuery{Offset: 3, Limit: 1})
+	if len(page.Entries) != 1 || page.Entries[0].Offset != 3 || !page.More {
+		t.Errorf("read(offset 3, limit 1) = %+v, want entry 3 with more", page)
+	}
+
+	page = log.read(TaskLogQuery{Tail: 2})
+	if len(page.Entries) != 2 || page.Entries[0].Message != "line 3" {
+		t.Errorf("read(tail 2) = %+v, want lines 3 and 4", page.Entries)
+	}
+
+	page = log.read(TaskLogQuery{Offset: 10})
+	if len(page.Entries) != 0 || page.NextOffset != 5 {
+		t.Errorf("read(offset 10) = %+v, want empty page at 5", page)
+	}
+}
+
+func TestTaskManager_TaskLogs(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTestingWithOptions(repo, Options{
+		Workers:     1,
+		RetryPolicy: model.RetryPolicy{MaxAttempts: 2, InitialBackoff: model.Duration(1)},
+	})
+
+	manager.RegisterExecutor("sync", func(ctx context.Context, task *model.Task) (any, error) {
+		logger := TaskLoggerFromContext(ctx)
+		logger.Infof("attempt %d", task.Attempt)
+		if task.Attempt == 1 {
+			logger.Errorf("upstream unavailable")
+			return nil, errors.New("upstream unavailable")
+		}
+		fmt.Fprintln(logger, "synced 10 records")
+		return "ok", nil
+	})
+
+	manager.CreateTask(TaskSpec{ID: "sync-1", Type: "sync"})
+	waitForStatus(t, manager, "sync-1", model.StatusCompleted)
+
+	page, err := manager.GetTaskLogs("sync-1", TaskLogQuery{})
+	if err != nil {
+		t.Fatalf("GetTaskLogs() error = %v, want nil", err)
+	}
+
+	if !page.Finished {
+		t.Error("Finished = false, want true for completed task")
+	}
+
+	var messages []string
+	for _, entry := range page.Entries {
+		messages = append(messages, entry.Source+": "+entry.Message)
+	}
+	got := strings.Join(messages, "\n")
+
+	for _, want := range []string{
+		"system: task created with status pending in queue default",
+		"executor: upstream unavailable",
+		"system: attempt 1 failed: upstream unavailable",
+		"executor: synced 10 records",
+		"system: task completed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("logs missing %q, got:\n%s", want, got)
+		}
+	}
+
+	manager.DeleteTask("sync-1")
+	if _, err := manager.GetTaskLogs("sync-1", TaskLogQuery{}); err == nil {
+		t.Error("GetTaskLogs() error = nil after delete, want not found")
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type WorkflowSpec struct {
+	ID    string
+	Input json.RawMessage
+	Steps []model.WorkflowStep
+}
+
+func (tm *TaskManager) CreateWorkflow(spec WorkflowSpec) (*model.Workflow, error) {
+	if tm.isClosing() {
+		tm.logger.WithField("workflow_id", spec.ID).Warn("Rejecting workflow: task manager is shutting down")
+		return nil, ErrShuttingDown
+	}
+
+	tm.logger.WithField("workflow_id", spec.ID).Info("Creating workflow")
+
+	steps, err := tm.validateWorkflow(spec)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"workflow_id": spec.ID,
+			"error":       err.Error(),
+		}).Warn("Invalid workflow specification")
+		return nil, err
+	}
+
+	workflow := &model.Workflow{
+		ID:         spec.ID,
+		Input:      spec.Input,
+		Steps:      steps,
+		Status:     model.WorkflowRunning,
+		StepStates: make([]model.StepState, len(steps)),
+		CreatedAt:  time.Now(),
+	}
+
+	for i, step := range steps {
+		workflow.StepStates[i].Name = step.Name
+	}
+
+	tm.workflowMutex.Lock()
+	defer tm.workflowMutex.Unlock()
+
+	err = tm.workflows.Create(workflow)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"workflow_id": spec.ID,
+			"error":       err.Error(),
+		}).Error("Failed to create workflow in repository")
+		return nil, fmt.Errorf("failed to create workflow: %w", err)
+	}
+
+	tm.advanceWorkflow(workflow)
+
+	tm.logger.WithFields(logrus.Fields{
+		"workflow_id": workflow.ID,
+		"steps":       len(workflow.Steps),
+	}).Info("Workflow created successfully")
+	return copyWorkflow(workflow), nil
+}
+
+func (tm *TaskManager) GetWorkflow(id string) (*model.Workflow, error) {
+	workflow, err := tm.workflows.GetByID(id)
+	if err != nil {
+		tm.logger.WithFields(logrus.Fields{
+			"workflow_id": id,
+			"error":       err.Error(),
+		}).Warn("Workflow not found")
+		return nil, err
+	}
+
+	tm.workflowMutex.Lock()
+	defer tm.workflowMutex.Unlock()
+
+	tm.refreshWorkflow(workflow)
+	return copyWorkflow(workflow), nil
+}
+
+// copyWorkflow возвращает копию, которую можно читать без workflowMutex;
+// вызывается под workflowMutex
+func copyWorkflow(workflow *model.Workflow) *model.Workflow {
+	copied := *workflow
+	copied.Steps = append([]model.WorkflowStep(nil), workflow.Steps...)
+	copied.CurrentSteps = append([]string(nil), workflow.CurrentSteps...)
+	copied.StepStates = append([]model.StepState(nil), workflow.StepStates...)
+	return &copied
+}
+
+// validateWorkflow проверяет шаги и подставляет зависимость от предыдущего шага.
+// Шаг может зависеть только от шагов, объявленных раньше, поэтому граф всегда ацикличен
+func (tm *TaskManager) validateWorkflow(spec WorkflowSpec) ([]model.WorkflowStep, error) {
+	if spec.ID == "" {
+		return nil, validationErrorf("invalid workflow: id is required")
+	}
+
+	if len(spec.Steps) == 0 {
+		return nil, validationErrorf("invalid workflow: at least one step is required")
+	}
+
+	steps := make([]model.WorkflowStep, len(spec.Steps))
+	declared := make(map[string]bool, len(spec.Steps))
+
+	for i, step := range spec.Steps {
+		if step.Name == "" {
+			return nil, validationErrorf("invalid workflow: step %d has no name", i+1)
+		}
+
+		if declared[step.Name] {
+			return nil, validationErrorf("invalid workflow: duplicate step name %q", step.Name)
+		}
+
+		if step.DependsOn == nil && i > 0 {
+			step.DependsOn = []string{spec.Steps[i-1].Name}
+		}
+
+		for _, dependency := range step.DependsOn {
+			if !declared[dependency] {
+				return nil, validationErrorf("invalid workflow: step %q depends on %q, which is not declared before it", step.Name, dependency)
+			}
+		}
+
+		stepSpec := taskSpecFromStep(step, "", nil)
+		if err := tm.validateSpec(&stepSpec); err != nil {
+			return nil, validationErrorf("invalid workflow: step %q: %w", step.Name, err)
+		}
+		step.Type = stepSpec.Type
+
+		declared[step.Name] = true
+		steps[i] = step
+	}
+
+	return steps, nil
+}
+
+// advanceWorkflow запускает шаги, все зависимости которых выполнены,
+// и подводит итог, если шагов больше не осталось; вызывается под workflowMutex
+func (tm *TaskManager) advanceWorkflow(workflow *model.Workflow) {
+	if workflow.IsCompleted() {
+		return
+	}
+
+	completed := make(map[string]int, len(workflow.Steps))
+	for i, state := range workflow.StepStates {
+		if state.Status == model.StatusCompleted {
+			completed[state.Name] = i
+		}
+	}
+
+	for i, step := range workflow.Steps {
+		state := &workflow.StepStates[i]
+		if state.TaskID != "" || !dependenciesCompleted(step, completed) {
+			continue
+		}
+
+		input, err := stepInput(workflow, step, completed)
+		if err != nil {
+			tm.finishWorkflow(workflow, model.WorkflowFailed, fmt.Sprintf("step %s: %v", step.Name, err))
+			return
+		}
+
+		spec := taskSpecFromStep(step, workflowTaskID(workflow.ID, step.Name), input)
+		spec.WorkflowID = workflow.ID
+		spec.WorkflowStep = step.Name
+
+		task, err := tm.createTask(spec)
+		if err != nil {
+			tm.finishWorkflow(workflow, model.WorkflowFailed, fmt.Sprintf("step %s: %v", step.Name, err))
+			return
+		}
+
+		state.TaskID = task.ID
+		state.Status = task.Status
+	}
+
+	if len(completed) == len(workflow.Steps) {
+		tm.finishWorkflow(workflow, model.WorkflowCompleted, "")
+		return
+	}
+
+	tm.refreshWorkflow(workflow)
+	tm.workflows.Update(workflow)
+}
+
+func (tm *TaskManager) workflowTaskFinished(task *model.Task) {
+	var abandoned []string
+
+	tm.workflowMutex.Lock()
+
+	workflow, err := tm.workflows.GetByID(task.WorkflowID)
+	if err != nil {
+		tm.workflowMutex.Unlock()
+		return
+	}
+
+	tm.refreshWorkflow(workflow)
+
+	if !workflow.IsCompleted() {
+		switch task.Status {
+		case model.StatusCompleted:
+			tm.advanceWorkflow(workflow)
+		case model.StatusFailed:
+			tm.finishWorkflow(workflow, model.WorkflowFailed, fmt.Sprintf("step %s failed: %s", task.WorkflowStep, task.Error))
+			abandoned = workflow.CurrentSteps
+		case model.StatusCancelled:
+			tm.finishWorkflow(workflow, model.WorkflowCancelled, fmt.Sprintf("step %s was cancelled", task.WorkflowStep))
+			abandoned = workflow.CurrentSteps
+		}
+	}
+
+	tm.workflowMutex.Unlock()
+
+	// отмена вызывает taskFinished, поэтому выполняется без workflowMutex
+	for _, stepName := range abandoned {
+		tm.CancelTask(workflowTaskID(workflow.ID, stepName))
+	}
+}
+
+// workflowTaskDeleted завершает конвейер с ошибкой, если удалена задача
+// шага, который еще не выполнен: без нее конвейер не может продолжиться
+func (tm *TaskManager) workflowTaskDeleted(task *model.Task) {
+	if task.IsCompleted() {
+		return
+	}
+
+	tm.workflowMutex.Lock()
+
+	workflow, err := tm.workflows.GetByID(task.WorkflowID)
+	if err != nil || workflow.IsCompleted() {
+		tm.workflowMutex.Unlock()
+		return
+	}
+
+	tm.finishWorkflow(workflow, model.WorkflowFailed, fmt.Sprintf("step %s task was deleted", task.WorkflowStep))
+	abandoned := workflow.CurrentSteps
+
+	tm.workflowMutex.Unlock()
+
+	for _, stepName := range abandoned {
+		tm.CancelTask(workflowTaskID(workflow.ID, stepName))
+	}
+}
+
+// finishWorkflow фиксирует итоговый статус; вызывается под workflowMutex
+func (tm *TaskManager) finishWorkflow(workflow *model.Workflow, status model.WorkflowStatus, reason string) {
+	completedAt := time.Now()
+	workflow.Status = status
+	workflow.Error = reason
+	workflow.CompletedAt = &completedAt
+
+	tm.refreshWorkflow(workflow)
+	tm.workflows.Update(workflow)
+
+	tm.logger.WithFields(logrus.Fields{
+		"workflow_id": workflow.ID,
+		"status":      status,
+		"reason":      reason,
+	}).Info("Workflow finished")
+}
+
+// refreshWorkflow копирует состояние дочерних задач в шаги. Для удаленной
+// задачи остается последнее известное состояние; вызывается под workflowMutex
+func (tm *TaskManager) refreshWorkflow(workflow *model.Workflow) {
+	workflow.CurrentSteps = nil
+
+	// поля задач меняются воркерами под mutex
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	for i := range workflow.StepStates {
+		state := &workflow.StepStates[i]
+		if state.TaskID == "" {
+			continue
+		}
+
+		task, err := tm.repo.GetByID(state.TaskID)
+		if err != nil {
+			continue
+		}
+
+		state.Status = task.Status
+		state.Result = task.Result
+		state.Error = task.Error
+		state.StartedAt = task.StartedAt
+		state.CompletedAt = task.CompletedAt
+		if task.CancelledAt != nil {
+			state.CompletedAt = task.CancelledAt
+		}
+
+		if !task.IsCompleted() {
+			workflow.CurrentSteps = append(workflow.CurrentSteps, state.Name)
+		}
+	}
+}
+
+func dependenciesCompleted(step model.WorkflowStep, completed map[string]int) bool {
+	for _, dependency := range step.DependsOn {
+		if _, done := completed[dependency]; !done {
+			return false
+		}
+	}
+	return true
+}
+
+// stepInput строит вход шага: вход workflow для первых шагов, результат
+// единственной зависимости или объект {"шаг": результат} при нескольких
+func stepInput(workflow *model.Workflow, step model.WorkflowStep, completed map[string]int) (json.RawMessage, error) {
+	switch len(step.DependsOn) {
+	case 0:
+		return workflow.Input, nil
+	case 1:
+		return workflow.StepStates[completed[step.DependsOn[0]]].Result, nil
+	}
+
+	inputs := make(map[string]json.RawMessage, len(step.DependsOn))
+	for _, dependency := range step.DependsOn {
+		inputs[dependency] = workflow.StepStates[completed[dependency]].Result
+	}
+
+	return json.Marshal(inputs)
+}
+
+func taskSpecFromStep(step model.WorkflowStep, id string, input json.RawMessage) TaskSpec {
+	return TaskSpec{
+		ID:          id,
+		Type:        step.Type,
+		Payload:     input,
+		Queue:       step.Queue,
+		Priority:    step.Priority,
+		Timeout:     step.Timeout.Duration(),
+		RetryPolicy: step.RetryPolicy,
+	}
+}
+
+func workflowTaskID(workflowID, stepName string) string {
+	return fmt.Sprintf("%s.%s", workflowID, stepName)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambutcha/taskflow/internal/model"
+	"github.com/bambutcha/taskflow/internal/repository"
+)
+
+func waitForWorkflow(t *testing.T, manager *TaskManager, id string, status model.WorkflowStatus) *model.Workflow {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		workflow, err := manager.GetWorkflow(id)
+		if err == nil && workflow.Status == status {
+			return workflow
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	workflow, _ := manager.GetWorkflow(id)
+	t.Fatalf("Workflow %s status = %v, want %v", id, workflow.Status, status)
+	return nil
+}
+
+func setupWorkflowManager(t *testing.T) *TaskManager {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	manager := NewTaskManagerForTesting(repo, 2)
+
+	// double принимает число и возвращает его удвоенным
+	manager.RegisterExecutor("double", func(ctx context.Context, task *model.Task) (any, error) {
+		var n int
+		if err := json.Unmarshal(task.Payload, &n); err != nil {
+			return nil, Permanent(err)
+		}
+		return n * 2, nil
+	})
+
+	// sum складывает результаты всех входящих шагов
+	manager.RegisterExecutor("sum", func(ctx context.Context, task *model.Task) (any, error) {
+		var inputs map[string]int
+		if err := json.Unmarshal(task.Payload, &inputs); err != nil {
+			return nil, Permanent(err)
+		}
+		total := 0
+		for _, n := range inputs {
+			total += n
+		}
+		return total, nil
+	})
+
+	manager.RegisterExecutor("broken", func(ctx context.Context, task *model.Task) (any, error) {
+		return nil, Permanent(fmt.Errorf("broken step"))
+	})
+
+	return manager
+}
+
+func TestTaskManager_Workflow_LinearChain(t *testing.T) {
+	manager := setupWorkflowManager(t)
+
+	_, err := manager.CreateWorkflow(WorkflowSpec{
+		ID:    "chain",
+		Input: json.RawMessage(`3`),
+		Steps: []model.WorkflowStep{
+			{Name: "first", Type: "double"},
+			{Name: "second", Type: "double"},
+			{Name: "third", Type: "double"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v, want nil", err)
+	}
+
+	workflow := waitForWorkflow(t, manager, "chain", model.WorkflowCompleted)
+
+	want := []string{"6", "12", "24"}
+	for i, state := range workflow.StepStates {
+		if string(state.Result) != want[i] {
+			t.Errorf("Step %s result = %s, want %s", state.Name, state.Result, want[i])
+		}
+	}
+
+	if len(workflow.CurrentSteps) != 0 {
+		t.Errorf("CurrentSteps = %v, want empty", workflow.CurrentSteps)
+	}
+
+	task, err := manager.GetTask("chain.second")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v, want nil", err)
+	}
+
+	if task.WorkflowID != "chain" || task.WorkflowStep != "second" {
+		t.Errorf("Task workflow = %s/%s, want chain/second", task.WorkflowID, task.WorkflowStep)
+	}
+}
+
+func TestTaskManager_Workflow_FanIn(t *testing.T) {
+	manager := setupWorkflowManager(t)
+
+	_, err := manager.CreateWorkflow(WorkflowSpec{
+		ID:    "fan-in",
+		Input: json.RawMessage(`5`),
+		Steps: []model.WorkflowStep{
+			{Name: "left", Type: "double"},
+			{Name: "right", Type: "double", DependsOn: []string{}},
+			{Name: "total", Type: "sum", DependsOn: []string{"left", "right"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v, want nil", err)
+	}
+
+	workflow := waitForWorkflow(t, manager, "fan-in", model.WorkflowCompleted)
+
+	if result := workflow.StepStates[2].Result; string(result) != "20" {
+		t.Errorf("Total result = %s, want %s", result, "20")
+	}
+}
+
+func TestTaskManager_Workflow_StepFailure(t *testing.T) {
+	manager := setupWorkflowManager(t)
+
+	_, err := manager.CreateWorkflow(WorkflowSpec{
+		ID:    "failing",
+		Input: json.RawMessage(`1`),
+		Steps: []model.WorkflowStep{
+			{Name: "first", Type: "double"},
+			{Name: "second", Type: "broken"},
+			{Name: "third", Type: "double"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v, want nil", err)
+	}
+
+	workflow := waitForWorkflow(t, manager, "failing", model.WorkflowFailed)
+
+	if !strings.Contains(workflow.Error, "second") {
+		t.Errorf("Workflow error = %q, want mention of failed step", workflow.Error)
+	}
+
+	if workflow.StepStates[2].TaskID != "" {
+		t.Errorf("Third step task = %q, want it never started", workflow.StepStates[2].TaskID)
+	}
+}
+
+func TestTaskManager_Workflow_StepDeleted(t *testing.T) {
+	manager := setupWorkflowManager(t)
+	manager.PauseQueue(DefaultQueue)
+
+	_, err := manager.CreateWorkflow(WorkflowSpec{
+		ID:    "orphaned",
+		Input: json.RawMessage(`1`),
+		Steps: []model.WorkflowStep{
+			{Name: "first", Type: "double"},
+			{Name: "second", Type: "double"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v, want nil", err)
+	}
+
+	if err := manager.DeleteTask("orphaned.first"); err != nil {
+		t.Fatalf("DeleteTask() error = %v, want nil", err)
+	}
+
+	workflow := waitForWorkflow(t, manager, "orphaned", model.WorkflowFailed)
+
+	if !strings.Contains(workflow.Error, "first") || !strings.Contains(workflow.Error, "deleted") {
+		t.Errorf("Workflow error = %q, want mention of deleted step", workflow.Error)
+	}
+}
+
+func TestTaskManager_CreateWorkflow_Invalid(t *testing.T) {
+	manager := setupWorkflowManager(t)
+
+	tests := []struct {
+		name  string
+		steps []model.WorkflowStep
+	}{
+		{"no steps", nil},
+		{"duplicate step", []model.WorkflowStep{{Name: "a", Type: "double"}, {Name: "a", Type: "double"}}},
+		{"forward dependency", []model.WorkflowStep{{Name: "a", Type: "double", DependsOn: []string{"b"}}, {Name: "b", Type: "double"}}},
+		{"unknown type", []model.WorkflowStep{{Name: "a", Type: "missing"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.CreateWorkflow(WorkflowSpec{ID: "invalid", Steps: tt.steps})
+			if err == nil || !strings.Contains(err.Error(), "invalid workflow") {
+				t.Errorf("CreateWorkflow() error = %v, want invalid workflow error", err)
+			}
+		})
+	}
+}